@@ -0,0 +1,315 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"portfolio/metrics"
+	"portfolio/soccer"
+	"portfolio/types"
+)
+
+/*
+========================================
+Soccer JSON API
+========================================
+*/
+
+// apiCacheControl is sent with successful API responses. Schedules change
+// rarely, so clients and proxies may reuse a response for a few minutes.
+const apiCacheControl = "public, max-age=300"
+
+func apiGamesHandler(w http.ResponseWriter, r *http.Request) {
+	teamCodes := soccer.ParseTeamCodes(r.URL.Query().Get("teams"))
+	if len(teamCodes) == 0 {
+		writeAPIError(w, http.StatusBadRequest, "missing_teams", "the teams query parameter is required")
+		return
+	}
+	if code, err := soccer.ValidateTeamCodes(teamCodes); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_team_code", code+": "+err.Error())
+		return
+	}
+	filter, err := soccer.ParseFilter(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_filter", err.Error())
+		return
+	}
+
+	resp, err := soccer.FetchGames(r.Context(), teamCodes)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "provider_error", "the schedule provider is unavailable")
+		return
+	}
+	games := soccer.FilterGames(resp.Games, filter, time.Now())
+	notModified := writeAPIJSON(w, r, types.SoccerGamesResponse{
+		Teams: teamCodes,
+		Count: len(games),
+		Games: games,
+	})
+	if notModified {
+		metrics.SoccerCacheHits.Inc()
+	}
+}
+
+func apiTeamHandler(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if _, err := soccer.ValidateTeamCodes([]string{code}); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_team_code", code+": "+err.Error())
+		return
+	}
+	filter, err := soccer.ParseFilter(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_filter", err.Error())
+		return
+	}
+
+	resp, err := soccer.FetchGames(r.Context(), []string{code})
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "provider_error", "the schedule provider is unavailable")
+		return
+	}
+	games := soccer.FilterGames(resp.Games, filter, time.Now())
+	notModified := writeAPIJSON(w, r, types.SoccerTeamResponse{
+		Code:  code,
+		Count: len(games),
+		Games: games,
+	})
+	if notModified {
+		metrics.SoccerCacheHits.Inc()
+	}
+}
+
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	writeAPIJSON(w, r, openAPIDocument())
+}
+
+// writeAPIJSON writes v as a cacheable JSON response. A strong ETag is
+// derived from the body so clients can revalidate with If-None-Match. It
+// reports whether the client's copy was current and 304 was sent instead.
+func writeAPIJSON(w http.ResponseWriter, r *http.Request, v any) (notModified bool) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "internal_error", "failed to encode response")
+		return false
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("Cache-Control", apiCacheControl)
+	w.Header().Set("ETag", etag)
+	// Compression weakens the ETag on the way out, so accept either form.
+	if strings.TrimPrefix(r.Header.Get("If-None-Match"), "W/") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+	return false
+}
+
+// writeAPIError writes a typed error envelope. Errors are never cached.
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(types.APIErrorResponse{
+		Error: types.APIError{Status: status, Code: code, Message: message},
+	})
+}
+
+/*
+========================================
+OpenAPI
+========================================
+*/
+
+// apiParam describes a path or query parameter of an API operation.
+type apiParam struct {
+	Name        string
+	In          string
+	Description string
+	Pattern     string
+	Format      string
+	Enum        []string
+	Required    bool
+}
+
+// gameFilterParams documents the optional filters accepted by ParseFilter.
+var gameFilterParams = []apiParam{
+	{Name: "from", In: "query", Description: "Only games on or after this date", Format: "date"},
+	{Name: "to", In: "query", Description: "Only games on or before this date", Format: "date"},
+	{Name: "upcoming", In: "query", Description: "Set to any value to hide games that have already kicked off"},
+	{Name: "venue", In: "query", Description: "Only home or away games of the requested team", Enum: []string{soccer.VenueHome, soccer.VenueAway}},
+}
+
+// apiOperation describes one documented API route. The OpenAPI document is
+// generated from this table and the response types it references.
+type apiOperation struct {
+	Method   string
+	Path     string
+	ID       string
+	Summary  string
+	Params   []apiParam
+	Response any
+	Errors   []int
+}
+
+var apiOperations = []apiOperation{
+	{
+		Method:  http.MethodGet,
+		Path:    "/api/v1/soccer/games",
+		ID:      "listGames",
+		Summary: "List scheduled games for one or more teams",
+		Params: append([]apiParam{
+			{Name: "teams", In: "query", Required: true, Description: "Comma-separated 6-digit team codes", Pattern: `^\d{6}([\s,;]+\d{6})*$`},
+		}, gameFilterParams...),
+		Response: types.SoccerGamesResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusBadGateway},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/api/v1/soccer/teams/{code}",
+		ID:      "getTeam",
+		Summary: "List scheduled games for a single team",
+		Params: append([]apiParam{
+			{Name: "code", In: "path", Required: true, Description: "6-digit team code", Pattern: `^\d{6}$`},
+		}, gameFilterParams...),
+		Response: types.SoccerTeamResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusBadGateway},
+	},
+}
+
+// openAPIDocument returns the OpenAPI 3 description of the JSON API. It is
+// built once from apiOperations and cached for the life of the process.
+var openAPIDocument = sync.OnceValue(func() map[string]any {
+	schemas := map[string]any{}
+	errorRef := schemaRef(reflect.TypeFor[types.APIErrorResponse](), schemas)
+
+	paths := map[string]any{}
+	for _, op := range apiOperations {
+		params := make([]map[string]any, 0, len(op.Params))
+		for _, p := range op.Params {
+			schema := map[string]any{"type": "string"}
+			if p.Pattern != "" {
+				schema["pattern"] = p.Pattern
+			}
+			if p.Format != "" {
+				schema["format"] = p.Format
+			}
+			if len(p.Enum) > 0 {
+				schema["enum"] = p.Enum
+			}
+			params = append(params, map[string]any{
+				"name":        p.Name,
+				"in":          p.In,
+				"required":    p.Required,
+				"description": p.Description,
+				"schema":      schema,
+			})
+		}
+
+		responses := map[string]any{
+			"200": map[string]any{
+				"description": "OK",
+				"headers": map[string]any{
+					"Cache-Control": map[string]any{"schema": map[string]any{"type": "string"}},
+					"ETag":          map[string]any{"schema": map[string]any{"type": "string"}},
+				},
+				"content": jsonContent(schemaRef(reflect.TypeOf(op.Response), schemas)),
+			},
+			"304": map[string]any{"description": "Not Modified"},
+		}
+		for _, status := range op.Errors {
+			responses[strconv.Itoa(status)] = map[string]any{
+				"description": http.StatusText(status),
+				"content":     jsonContent(errorRef),
+			}
+		}
+
+		item, _ := paths[op.Path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[op.Path] = item
+		}
+		item[strings.ToLower(op.Method)] = map[string]any{
+			"operationId": op.ID,
+			"summary":     op.Summary,
+			"tags":        []string{"soccer"},
+			"parameters":  params,
+			"responses":   responses,
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Craig Johnson Portfolio API",
+			"version":     "1.0.0",
+			"description": "Soccer schedule data from the Let's Play Soccer schedule tool.",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+})
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// schemaRef registers the JSON schema for t in schemas and returns a $ref
+// to it. Struct fields are described by their json tags.
+func schemaRef(t reflect.Type, schemas map[string]any) map[string]any {
+	if _, ok := schemas[t.Name()]; !ok {
+		schemas[t.Name()] = map[string]any{} // reserve the name to stop recursion
+		schemas[t.Name()] = structSchema(t, schemas)
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+}
+
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		props[name] = typeSchema(field.Type, schemas)
+		required = append(required, name)
+	}
+	return map[string]any{"type": "object", "properties": props, "required": required}
+}
+
+func typeSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := typeSchema(t.Elem(), schemas)
+		// OpenAPI 3.0 ignores the siblings of a $ref, so a nullable
+		// reference has to be wrapped.
+		if _, ok := schema["$ref"]; ok {
+			return map[string]any{"allOf": []any{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return map[string]any{"type": "integer"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), schemas)}
+	case reflect.Struct:
+		return schemaRef(t, schemas)
+	default:
+		panic("openapi: unsupported kind " + t.Kind().String())
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"portfolio/metrics"
+)
+
+func TestWriteAPIJSONRevalidation(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if writeAPIJSON(rec, httptest.NewRequest(http.MethodGet, "/", nil), map[string]int{"n": 1}) {
+		t.Fatal("first response reported as not modified")
+	}
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != apiCacheControl {
+		t.Fatalf("got %d with Cache-Control %q", rec.Code, rec.Header().Get("Cache-Control"))
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+
+	for _, sent := range []string{etag, "W/" + etag} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", sent)
+		rec := httptest.NewRecorder()
+		if !writeAPIJSON(rec, req, map[string]int{"n": 1}) || rec.Code != http.StatusNotModified {
+			t.Errorf("If-None-Match %s: got %d, want 304", sent, rec.Code)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: 304 has a body", sent)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	if writeAPIJSON(rec, req, map[string]int{"n": 2}) || rec.Code != http.StatusOK {
+		t.Errorf("changed body: got %d, want 200", rec.Code)
+	}
+}
+
+// Only the soccer endpoints count towards soccer_cache_hits_total.
+func TestSoccerCacheHits(t *testing.T) {
+	revalidate := func(h http.HandlerFunc, target string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+		rec = httptest.NewRecorder()
+		h(rec, req)
+		if rec.Code != http.StatusNotModified {
+			t.Fatalf("GET %s revalidation: got %d, want 304", target, rec.Code)
+		}
+	}
+
+	before := testutil.ToFloat64(metrics.SoccerCacheHits)
+	revalidate(openAPIHandler, "/api/openapi.json")
+	if got := testutil.ToFloat64(metrics.SoccerCacheHits); got != before {
+		t.Errorf("openapi.json 304 counted as a soccer cache hit")
+	}
+	revalidate(apiGamesHandler, "/api/v1/soccer/games?teams=123456")
+	if got := testutil.ToFloat64(metrics.SoccerCacheHits); got != before+1 {
+		t.Errorf("soccer_cache_hits_total = %g, want %g", got, before+1)
+	}
+}
+
+// OpenAPI 3.0 ignores every sibling of a $ref, so none may have any.
+func TestOpenAPIRefsHaveNoSiblings(t *testing.T) {
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if _, ok := v["$ref"]; ok && len(v) > 1 {
+				t.Errorf("%s: $ref has siblings: %v", path, v)
+			}
+			for k, child := range v {
+				walk(path+"/"+k, child)
+			}
+		case []any:
+			for i, child := range v {
+				walk(fmt.Sprintf("%s/%d", path, i), child)
+			}
+		case []map[string]any:
+			for i, child := range v {
+				walk(fmt.Sprintf("%s/%d", path, i), child)
+			}
+		}
+	}
+	walk("#", openAPIDocument())
+}
+
+func TestTypeSchemaNullable(t *testing.T) {
+	type nested struct {
+		N int `json:"n"`
+	}
+	type doc struct {
+		Score  *int    `json:"score"`
+		Nested *nested `json:"nested"`
+	}
+	schemas := map[string]any{}
+	props := typeSchema(reflect.TypeFor[doc](), schemas)
+	if props["$ref"] != "#/components/schemas/doc" {
+		t.Fatalf("typeSchema(doc) = %v", props)
+	}
+	fields := schemas["doc"].(map[string]any)["properties"].(map[string]any)
+
+	score := fields["score"].(map[string]any)
+	if score["type"] != "integer" || score["nullable"] != true {
+		t.Errorf("score schema = %v, want a nullable integer", score)
+	}
+	nestedSchema := fields["nested"].(map[string]any)
+	allOf, _ := nestedSchema["allOf"].([]any)
+	if nestedSchema["nullable"] != true || len(allOf) != 1 || allOf[0].(map[string]any)["$ref"] != "#/components/schemas/nested" {
+		t.Errorf("nested schema = %v, want a nullable allOf wrapping the $ref", nestedSchema)
+	}
+}
@@ -0,0 +1,344 @@
+// Package assets fingerprints static files so they can be cached forever.
+// A Manifest hashes every file at startup and maps each logical path such as
+// "css/styles.css" to a content-addressed one such as
+// "css/styles.3f2a9c1b7e.css". Templ components build URLs with URL, and
+// the Manifest's handler serves both forms, preferring precompressed .br and
+// .gz siblings produced by Compress. The Manifest also records a Subresource
+// Integrity hash for every file, returned by Integrity.
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"portfolio/middleware"
+)
+
+// Prefix is the URL path under which static files are served.
+const Prefix = "/static/"
+
+// Cache-Control values for fingerprinted and plain asset URLs. A
+// fingerprinted URL changes whenever the file does, so it never needs
+// revalidating; plain URLs are revalidated against their ETag.
+const (
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	plainCacheControl     = "no-cache"
+)
+
+// hashLen is the number of hex digits of the SHA-256 digest kept in names.
+const hashLen = 10
+
+// entry describes one static file.
+type entry struct {
+	hashed    string // fingerprinted path, relative to the static root
+	etag      string
+	integrity string   // Subresource Integrity metadata
+	encodings []string // content codings with a precompressed sibling
+}
+
+// Manifest maps static files to their fingerprinted paths.
+type Manifest struct {
+	fsys     fs.FS
+	files    map[string]entry  // logical path -> entry
+	byHashed map[string]string // fingerprinted path -> logical path
+}
+
+// NewManifest hashes every file in fsys.
+func NewManifest(fsys fs.FS) (*Manifest, error) {
+	m := &Manifest{
+		fsys:     fsys,
+		files:    make(map[string]entry),
+		byHashed: make(map[string]string),
+	}
+	var siblings []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if _, _, ok := siblingOf(name); ok {
+			siblings = append(siblings, name)
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])[:hashLen]
+		hashed := fingerprint(name, hash)
+		m.files[name] = entry{hashed: hashed, etag: `"` + hash + `"`, integrity: integrityOf(data)}
+		m.byHashed[hashed] = name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range siblings {
+		base, encoding, _ := siblingOf(name)
+		e, ok := m.files[base]
+		if !ok {
+			continue
+		}
+		// A sibling left over from an older version of its source would
+		// be served in its place, so only matching ones are offered.
+		match, err := siblingMatches(fsys, name, base, encoding)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			e.encodings = append(e.encodings, encoding)
+			m.files[base] = e
+		}
+	}
+	return m, nil
+}
+
+// siblingMatches reports whether the precompressed sibling name decodes to
+// the contents of base. A sibling that does not decode does not match.
+func siblingMatches(fsys fs.FS, name, base, encoding string) (bool, error) {
+	compressed, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return false, err
+	}
+	source, err := fs.ReadFile(fsys, base)
+	if err != nil {
+		return false, err
+	}
+	data, err := decode(encoding, compressed)
+	return err == nil && bytes.Equal(data, source), nil
+}
+
+// NewLiveManifest returns a manifest that does not fingerprint, for serving
+// files that may change on disk while the server runs. URL returns plain
+// paths and every response is revalidated.
+func NewLiveManifest(fsys fs.FS) *Manifest {
+	return &Manifest{fsys: fsys}
+}
+
+// fingerprint inserts hash before the extension: "css/a.css" becomes
+// "css/a.<hash>.css".
+func fingerprint(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// URL returns the URL for the static file name, relative to the static root.
+// Files missing from the manifest get their plain URL.
+func (m *Manifest) URL(name string) string {
+	if e, ok := m.files[name]; ok {
+		return Prefix + e.hashed
+	}
+	return Prefix + name
+}
+
+// Integrity returns the Subresource Integrity metadata of the static file
+// name, such as "sha384-...", for a script or stylesheet integrity
+// attribute. Live manifests hash the file as it is now on disk. Unknown
+// files return "".
+func (m *Manifest) Integrity(name string) string {
+	if !m.live() {
+		return m.files[name].integrity
+	}
+	data, err := fs.ReadFile(m.fsys, name)
+	if err != nil {
+		return ""
+	}
+	return integrityOf(data)
+}
+
+func integrityOf(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Require reports every name that is not a file in the static tree, so a
+// missing vendored asset stops the server at startup instead of breaking
+// pages.
+func (m *Manifest) Require(names ...string) error {
+	var errs []error
+	for _, name := range names {
+		if info, err := fs.Stat(m.fsys, name); err != nil || info.IsDir() {
+			errs = append(errs, fmt.Errorf("static file %s is missing", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Copy writes every static file into dir under both its plain and its
+// fingerprinted path, so pages rendered with either kind of URL can be
+// served from dir by a plain file server. Precompressed siblings are left
+// out; static hosts negotiate compression themselves. Copy is only
+// meaningful for fingerprinting manifests.
+func (m *Manifest) Copy(dir string) error {
+	for name, e := range m.files {
+		data, err := fs.ReadFile(m.fsys, name)
+		if err != nil {
+			return err
+		}
+		for _, rel := range []string{name, e.hashed} {
+			dst := filepath.Join(dir, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(dst, data, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FS returns the static tree the manifest describes.
+func (m *Manifest) FS() fs.FS {
+	return m.fsys
+}
+
+// Handler serves the static tree. It expects request paths relative to the
+// static root, i.e. mounted behind http.StripPrefix(Prefix, ...).
+// Fingerprinted paths are served with a year-long immutable Cache-Control;
+// plain paths carry an ETag and must be revalidated. Requests for files
+// that do not exist, directories and precompressed siblings are passed to
+// notFound, or answered with http.NotFound when it is nil.
+func (m *Manifest) Handler(notFound http.Handler) http.Handler {
+	if notFound == nil {
+		notFound = http.NotFoundHandler()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if _, _, ok := siblingOf(name); ok {
+			notFound.ServeHTTP(w, r) // compressed variants are only served by negotiation
+			return
+		}
+		if logical, ok := m.byHashed[name]; ok {
+			w.Header().Set("Cache-Control", immutableCacheControl)
+			m.serve(w, r, logical)
+			return
+		}
+		if !m.exists(name) {
+			notFound.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", plainCacheControl)
+		m.serve(w, r, name)
+	})
+}
+
+// exists reports whether name is a file in the static tree.
+func (m *Manifest) exists(name string) bool {
+	if !m.live() {
+		_, ok := m.files[name]
+		return ok
+	}
+	info, err := fs.Stat(m.fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+// serve writes the logical file name, or its precompressed sibling when the
+// client accepts one.
+func (m *Manifest) serve(w http.ResponseWriter, r *http.Request, name string) {
+	e, known := m.files[name]
+	if known {
+		w.Header().Set("ETag", e.etag)
+	}
+
+	offered := e.encodings
+	if m.live() {
+		offered = m.siblingsOnDisk(name)
+	}
+	if len(offered) > 0 {
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+	// Offer in the server's order of preference, not the manifest's.
+	var available []string
+	for _, enc := range compressEncodings {
+		if slices.Contains(offered, enc.name) {
+			available = append(available, enc.name)
+		}
+	}
+	encoding := middleware.PreferredEncoding(r, available...)
+	if encoding == "" {
+		http.ServeFileFS(w, r, m.fsys, name)
+		return
+	}
+
+	ct := mime.TypeByExtension(path.Ext(name))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("Content-Encoding", encoding)
+	if known {
+		w.Header().Set("ETag", strings.TrimSuffix(e.etag, `"`)+"-"+encoding+`"`)
+	}
+	http.ServeFileFS(w, r, m.fsys, name+extensionFor(encoding))
+}
+
+// live reports whether the manifest serves files that may change on disk.
+func (m *Manifest) live() bool {
+	return m.files == nil
+}
+
+// siblingsOnDisk returns the content codings with a precompressed sibling
+// of name, checked at request time for live manifests. Decoding every
+// sibling per request would be wasteful, so a sibling older than name is
+// taken to be stale instead.
+func (m *Manifest) siblingsOnDisk(name string) []string {
+	source, err := fs.Stat(m.fsys, name)
+	if err != nil {
+		return nil
+	}
+	var found []string
+	for _, enc := range compressEncodings {
+		if info, err := fs.Stat(m.fsys, name+enc.ext); err == nil && !info.ModTime().Before(source.ModTime()) {
+			found = append(found, enc.name)
+		}
+	}
+	return found
+}
+
+type manifestKey struct{}
+
+// NewContext returns a copy of ctx carrying m.
+func NewContext(ctx context.Context, m *Manifest) context.Context {
+	return context.WithValue(ctx, manifestKey{}, m)
+}
+
+// URL returns the fingerprinted URL of the static file name using the
+// manifest carried by ctx, e.g. assets.URL(ctx, "css/styles.css") in a templ
+// component. Without a manifest it returns the plain URL.
+func URL(ctx context.Context, name string) string {
+	if m, ok := ctx.Value(manifestKey{}).(*Manifest); ok {
+		return m.URL(name)
+	}
+	return Prefix + name
+}
+
+// Integrity returns the Subresource Integrity metadata of the static file
+// name using the manifest carried by ctx, or "" without a manifest.
+func Integrity(ctx context.Context, name string) string {
+	if m, ok := ctx.Value(manifestKey{}).(*Manifest); ok {
+		return m.Integrity(name)
+	}
+	return ""
+}
+
+// Middleware stores m in every request's context so components can call URL.
+func (m *Manifest) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), m)))
+	})
+}
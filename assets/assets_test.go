@@ -0,0 +1,266 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"portfolio/middleware"
+)
+
+var testFS = fstest.MapFS{
+	"css/styles.css": {Data: []byte("body{color:red}")},
+	"js/main.js":     {Data: []byte("console.log(1)")},
+	"robots.txt":     {Data: []byte("User-agent: *")},
+}
+
+func testManifest(t *testing.T) *Manifest {
+	t.Helper()
+	m, err := NewManifest(testFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestURL(t *testing.T) {
+	m := testManifest(t)
+	tests := []struct {
+		name string
+		want string
+	}{
+		// The hash is the first 10 hex digits of the file's SHA-256.
+		{"css/styles.css", "/static/css/styles.15c42ab776.css"},
+		{"js/main.js", "/static/js/main.0a286891c1.js"},
+		{"missing.css", "/static/missing.css"},
+	}
+	for _, tt := range tests {
+		if got := m.URL(tt.name); got != tt.want {
+			t.Errorf("URL(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		if got := URL(NewContext(context.Background(), m), tt.name); got != tt.want {
+			t.Errorf("URL(ctx, %q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := URL(context.Background(), "css/styles.css"); got != "/static/css/styles.css" {
+		t.Errorf("URL without a manifest = %q, want the plain URL", got)
+	}
+	if got := NewLiveManifest(testFS).URL("css/styles.css"); got != "/static/css/styles.css" {
+		t.Errorf("live URL = %q, want the plain URL", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	m := testManifest(t)
+	hashed := m.URL("css/styles.css")
+	tests := []struct {
+		name         string
+		manifest     *Manifest
+		path         string
+		ifNoneMatch  string
+		wantStatus   int
+		wantCache    string
+		wantETag     string
+		wantBodySize int // -1 to skip
+	}{
+		{"fingerprinted", m, hashed, "", http.StatusOK, immutableCacheControl, `"15c42ab776"`, 15},
+		{"plain", m, "/static/css/styles.css", "", http.StatusOK, plainCacheControl, `"15c42ab776"`, 15},
+		{"plain revalidated", m, "/static/css/styles.css", `"15c42ab776"`, http.StatusNotModified, plainCacheControl, `"15c42ab776"`, 0},
+		// Error responses drop Cache-Control so they are not cached.
+		{"stale hash", m, "/static/css/styles.0000000000.css", "", http.StatusNotFound, "", "", -1},
+		{"missing", m, "/static/nope.css", "", http.StatusNotFound, "", "", -1},
+		{"live", NewLiveManifest(testFS), "/static/css/styles.css", "", http.StatusOK, plainCacheControl, "", 15},
+		{"live ignores hashes", NewLiveManifest(testFS), hashed, "", http.StatusNotFound, "", "", -1},
+	}
+	for _, tt := range tests {
+		h := http.StripPrefix(Prefix, tt.manifest.Handler(nil))
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.wantStatus)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != tt.wantCache {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.name, got, tt.wantCache)
+		}
+		if got := rec.Header().Get("ETag"); got != tt.wantETag {
+			t.Errorf("%s: ETag = %q, want %q", tt.name, got, tt.wantETag)
+		}
+		if tt.wantBodySize >= 0 && rec.Body.Len() != tt.wantBodySize {
+			t.Errorf("%s: body is %d bytes, want %d", tt.name, rec.Body.Len(), tt.wantBodySize)
+		}
+	}
+}
+
+// Every fingerprinted path maps back to its logical file, and no two files
+// share one.
+func TestByHashed(t *testing.T) {
+	m := testManifest(t)
+	if len(m.byHashed) != len(testFS) {
+		t.Errorf("%d fingerprinted paths for %d files", len(m.byHashed), len(testFS))
+	}
+	for name, e := range m.files {
+		if got := m.byHashed[e.hashed]; got != name {
+			t.Errorf("byHashed[%q] = %q, want %q", e.hashed, got, name)
+		}
+	}
+}
+
+func TestIntegrity(t *testing.T) {
+	// openssl dgst -sha384 -binary css/styles.css | base64
+	const want = "sha384-8U9HYzsHbf55cFZyiWIE29+QPYQ9WO+U5uT/ViFw0TOwM2Fbbb74ZegzRV/nvwrD"
+	m := testManifest(t)
+	if got := m.Integrity("css/styles.css"); got != want {
+		t.Errorf("Integrity = %q, want %q", got, want)
+	}
+	if got := Integrity(NewContext(context.Background(), m), "css/styles.css"); got != want {
+		t.Errorf("Integrity(ctx) = %q, want %q", got, want)
+	}
+	if got := m.Integrity("missing.js"); got != "" {
+		t.Errorf("Integrity of a missing file = %q, want empty", got)
+	}
+	if got := Integrity(context.Background(), "css/styles.css"); got != "" {
+		t.Errorf("Integrity without a manifest = %q, want empty", got)
+	}
+
+	// Live manifests hash the file as it is when asked.
+	live := fstest.MapFS{"css/styles.css": {Data: []byte("body{color:blue}")}}
+	lm := NewLiveManifest(live)
+	before := lm.Integrity("css/styles.css")
+	live["css/styles.css"] = &fstest.MapFile{Data: testFS["css/styles.css"].Data}
+	if got := lm.Integrity("css/styles.css"); got != want || got == before {
+		t.Errorf("live Integrity after a change = %q, want %q", got, want)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	m := testManifest(t)
+	if err := m.Require("css/styles.css", "js/main.js"); err != nil {
+		t.Errorf("Require of present files = %v", err)
+	}
+	err := m.Require("css/styles.css", "vendor/htmx.min.js", "css", "fonts/inter.woff2")
+	want := "static file vendor/htmx.min.js is missing\n" +
+		"static file css is missing\n" +
+		"static file fonts/inter.woff2 is missing"
+	if err == nil || err.Error() != want {
+		t.Errorf("Require = %v, want\n%s", err, want)
+	}
+}
+
+func TestPrecompressedSiblings(t *testing.T) {
+	css := []byte("body{color:red}")
+	gz, err := encode(middleware.EncodingGzip, css)
+	if err != nil {
+		t.Fatal(err)
+	}
+	br, err := encode(middleware.EncodingBrotli, css)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := encode(middleware.EncodingBrotli, []byte("body{color:blue}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"a.css":     {Data: css},
+		"a.css.gz":  {Data: gz},
+		"a.css.br":  {Data: br},
+		"b.css":     {Data: css},
+		"b.css.br":  {Data: stale},
+		"c.css":     {Data: css},
+		"c.css.gz":  {Data: []byte("not gzip")},
+		"orphan.gz": {Data: gz},
+	}
+	m, err := NewManifest(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"a.css", []string{middleware.EncodingGzip, middleware.EncodingBrotli}},
+		{"b.css", nil},
+		{"c.css", nil},
+	}
+	for _, tt := range tests {
+		got := m.files[tt.name].encodings
+		if !slices.Equal(slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(tt.want))) {
+			t.Errorf("%s: encodings %q, want %q", tt.name, got, tt.want)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/"+tt.name, nil)
+		req.Header.Set("Accept-Encoding", "br, gzip")
+		rec := httptest.NewRecorder()
+		m.Handler(nil).ServeHTTP(rec, req)
+		if len(tt.want) == 0 && (rec.Header().Get("Content-Encoding") != "" || !bytes.Equal(rec.Body.Bytes(), css)) {
+			t.Errorf("%s: served %q encoded as %q, want the source", tt.name, rec.Body.Bytes(), rec.Header().Get("Content-Encoding"))
+		}
+	}
+}
+
+// Live manifests cannot afford to decode siblings per request, so they skip
+// ones older than their source.
+func TestLiveSiblingsOlderThanSource(t *testing.T) {
+	css := []byte("body{color:red}")
+	gz, err := encode(middleware.EncodingGzip, css)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	fsys := fstest.MapFS{
+		"fresh.css":    {Data: css, ModTime: now},
+		"fresh.css.gz": {Data: gz, ModTime: now},
+		"stale.css":    {Data: css, ModTime: now},
+		"stale.css.gz": {Data: gz, ModTime: now.Add(-time.Minute)},
+	}
+	m := NewLiveManifest(fsys)
+	for name, want := range map[string]string{"fresh.css": middleware.EncodingGzip, "stale.css": ""} {
+		req := httptest.NewRequest(http.MethodGet, "/"+name, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		m.Handler(nil).ServeHTTP(rec, req)
+		if got := rec.Header().Get("Content-Encoding"); got != want {
+			t.Errorf("%s: Content-Encoding = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestHandlerNotFound(t *testing.T) {
+	var misses []string
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		misses = append(misses, r.URL.Path)
+		w.WriteHeader(http.StatusTeapot)
+	})
+	paths := []string{"/missing.css", "/css", "/css/", "/css/styles.css.gz", "/css/styles.0000000000.css"}
+	for _, m := range []*Manifest{testManifest(t), NewLiveManifest(testFS)} {
+		misses = nil
+		h := m.Handler(notFound)
+		for _, p := range paths {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, p, nil))
+			if rec.Code != http.StatusTeapot {
+				t.Errorf("live=%v %s: status %d, want the notFound handler's", m.live(), p, rec.Code)
+			}
+		}
+		if !slices.Equal(misses, paths) {
+			t.Errorf("live=%v: notFound saw %q, want %q", m.live(), misses, paths)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/css/styles.css", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("live=%v: existing file got %d", m.live(), rec.Code)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package assets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+
+	"portfolio/middleware"
+)
+
+// compressEncodings lists the precompressed variants in order of preference.
+var compressEncodings = []struct {
+	name string // Content-Encoding value
+	ext  string // sibling file suffix
+}{
+	{middleware.EncodingBrotli, ".br"},
+	{middleware.EncodingGzip, ".gz"},
+}
+
+// compressibleTypes are the media types worth precompressing. Images other
+// than SVG and icons are already compressed.
+var compressibleTypes = []string{
+	"text/css",
+	"text/html",
+	"text/plain",
+	"text/javascript",
+	"application/javascript",
+	"application/json",
+	"application/manifest+json",
+	"application/xml",
+	"image/svg+xml",
+	"image/x-icon",
+}
+
+// maxCompressedRatio is the largest compressed/original size ratio for
+// which a sibling is kept; smaller savings are not worth the extra file.
+const maxCompressedRatio = 0.9
+
+// siblingOf reports whether name is a precompressed sibling, returning the
+// file it encodes and its content coding.
+func siblingOf(name string) (base, encoding string, ok bool) {
+	for _, enc := range compressEncodings {
+		if b, found := strings.CutSuffix(name, enc.ext); found {
+			return b, enc.name, true
+		}
+	}
+	return "", "", false
+}
+
+func extensionFor(encoding string) string {
+	for _, enc := range compressEncodings {
+		if enc.name == encoding {
+			return enc.ext
+		}
+	}
+	return ""
+}
+
+// Compress writes .br and .gz siblings next to every compressible file of at
+// least minSize bytes under dir, at maximum compression. Siblings that would
+// not save at least 10% are not written, and stale ones are removed. A line
+// per file is reported to out.
+func Compress(dir string, minSize int64, out io.Writer) error {
+	root := os.DirFS(dir)
+	return fs.WalkDir(root, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if base, _, ok := siblingOf(name); ok {
+			// Remove siblings whose source is gone.
+			if _, err := fs.Stat(root, base); err != nil {
+				return os.Remove(filepath.Join(dir, filepath.FromSlash(name)))
+			}
+			return nil
+		}
+		mediaType, _, _ := strings.Cut(mime.TypeByExtension(path.Ext(name)), ";")
+		if !slices.Contains(compressibleTypes, mediaType) {
+			return nil
+		}
+		data, err := fs.ReadFile(root, name)
+		if err != nil {
+			return err
+		}
+		if int64(len(data)) < minSize {
+			return nil
+		}
+
+		var report []string
+		for _, enc := range compressEncodings {
+			compressed, err := encode(enc.name, data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			target := filepath.Join(dir, filepath.FromSlash(name)+enc.ext)
+			if float64(len(compressed)) > float64(len(data))*maxCompressedRatio {
+				if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				report = append(report, enc.name+" skipped")
+				continue
+			}
+			if err := os.WriteFile(target, compressed, 0o644); err != nil {
+				return err
+			}
+			report = append(report, fmt.Sprintf("%s %d (%.0f%%)", enc.name, len(compressed), 100*float64(len(compressed))/float64(len(data))))
+		}
+		_, err = fmt.Fprintf(out, "%s (%d bytes): %s\n", name, len(data), strings.Join(report, ", "))
+		return err
+	})
+}
+
+func encode(encoding string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case middleware.EncodingBrotli:
+		w = brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	case middleware.EncodingGzip:
+		gw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		w = gw
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decode reverses encode.
+func decode(encoding string, data []byte) ([]byte, error) {
+	var r io.Reader
+	switch encoding {
+	case middleware.EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(data))
+	case middleware.EncodingGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	default:
+		return nil, fmt.Errorf("unknown content coding %q", encoding)
+	}
+	return io.ReadAll(r)
+}
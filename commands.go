@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"portfolio/assets"
+	"portfolio/components/layouts"
+	"portfolio/config"
+	"portfolio/content"
+	"portfolio/export"
+	"portfolio/health"
+	"portfolio/logging"
+	"portfolio/middleware"
+	"portfolio/reqctx"
+)
+
+/*
+========================================
+Commands
+========================================
+*/
+
+// commands are run when the first argument names one; otherwise the server
+// starts.
+var commands = map[string]func(args []string) error{
+	"assets":   assetsCommand,
+	"export":   exportCommand,
+	"validate": validateCommand,
+}
+
+// runCommand runs the subcommand named by args[0], if any, and reports
+// whether one was found.
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return false
+	}
+	if err := cmd(args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "portfolio %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+	}
+	return true
+}
+
+func assetsCommand(args []string) error {
+	if len(args) == 0 || args[0] != "compress" {
+		return errors.New("usage: portfolio assets compress [--dir static] [--min-size 1024]")
+	}
+	fs := flag.NewFlagSet("assets compress", flag.ContinueOnError)
+	dir := fs.String("dir", "static", "static directory to compress in place")
+	minSize := fs.Int64("min-size", 1024, "smallest file, in bytes, worth compressing")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	return assets.Compress(*dir, *minSize, os.Stdout)
+}
+
+// validateCommand checks the content files, printing one line per
+// violation, and fails if there are any. It checks the files the server
+// would load: those in --content-dir, or in content.dir from the config
+// file and environment, over the embedded copy.
+func validateCommand(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	configFile := fs.String("config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	contentDir := fs.String("content-dir", "", "content directory to check instead of content.dir")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	configArgs := []string{}
+	if *configFile != "" {
+		configArgs = append(configArgs, "--config", *configFile)
+	}
+	if *contentDir != "" {
+		configArgs = append(configArgs, "--content-dir", *contentDir)
+	}
+	loaded, _, err := config.Load(configArgs, os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	static, err := staticAssets(loaded.Server.StaticDir)
+	if err != nil {
+		return err
+	}
+	c, err := content.Load(loaded.Content.Dir)
+	if err != nil {
+		return err
+	}
+	violations := c.Check(static)
+	for _, v := range violations {
+		fmt.Println(v)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d content violations", len(violations))
+	}
+	fmt.Println("content OK")
+	return nil
+}
+
+// exportCommand renders the site into a directory for a static host. Page
+// content comes from the same config file and environment as the server.
+func exportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := fs.String("out", "dist", "directory to write the site into")
+	configFile := fs.String("config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	soccerOrigin := fs.String("soccer-origin", "", "origin of a running server that hosts the soccer tool, e.g. https://craigdevjohnson.com; the tool is left out when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	origin := strings.TrimSuffix(*soccerOrigin, "/")
+	if origin != "" {
+		if u, err := url.Parse(origin); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.Path != "" {
+			return fmt.Errorf("--soccer-origin %q is not an http(s) origin", *soccerOrigin)
+		}
+	}
+
+	var configArgs []string
+	if *configFile != "" {
+		configArgs = []string{"--config", *configFile}
+	}
+	loaded, _, err := config.Load(configArgs, os.Getenv)
+	if err != nil {
+		return err
+	}
+	cfg = loaded
+
+	// Only problems are worth reporting; every page render would otherwise
+	// write an access log line.
+	logger, err := logging.New(os.Stderr, logging.Options{Format: logging.FormatText, Level: slog.LevelWarn})
+	if err != nil {
+		return err
+	}
+	static, err := staticAssets(cfg.Server.StaticDir)
+	if err != nil {
+		return err
+	}
+	// Always fingerprint: exported files are a snapshot and can be cached
+	// forever.
+	manifest, err := assets.NewManifest(static)
+	if err != nil {
+		return err
+	}
+	if err := manifest.Require(layouts.VendorAssets...); err != nil {
+		return err
+	}
+	if siteContent, err = content.NewProvider(cfg.Content.Dir, static); err != nil {
+		return err
+	}
+
+	return newExportSite(logger, manifest, origin).Write(*out, os.Stdout)
+}
+
+// newExportSite describes the export of the site rendered with static.
+// Links to the soccer tool point at soccerOrigin, or are left out when it
+// is empty.
+func newExportSite(logger *slog.Logger, static *assets.Manifest, soccerOrigin string) export.Site {
+	site := export.Site{
+		Handler: newHandlerWith(logger, static, new(health.Registry), exportVisitor(soccerOrigin)),
+		Static:  static,
+		Pages:   exportPages(),
+	}
+	if soccerOrigin != "" {
+		site.Links = map[string]string{"/soccer": soccerOrigin + "/soccer"}
+		site.Redirects = []export.Redirect{
+			{From: "/soccer", To: soccerOrigin + "/soccer", Status: 302},
+			{From: "/soccer/*", To: soccerOrigin + "/soccer/:splat", Status: 302},
+			{From: "/api/*", To: soccerOrigin + "/api/:splat", Status: 302},
+		}
+	}
+	return site
+}
+
+// exportVisitor stands in for the per-visitor middleware when exporting.
+// Exported pages are cached and shared, so they carry no CSRF token, and
+// link to the soccer tool only when soccerOrigin serves it.
+func exportVisitor(soccerOrigin string) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqctx.From(r.Context()).NoSoccer = soccerOrigin == ""
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// exportPages lists what export renders. Pages whose sections load lazily
+// are rendered from their fragment routes, which fill the sections in, so
+// the static pages need no follow-up requests. Fragments htmx selects by
+// query string are written to one file per choice and linked directly,
+// since static hosts ignore query strings.
+func exportPages() []export.Page {
+	pages := []export.Page{
+		{URL: "/", File: "index.html"},
+		{URL: "/about", File: "about/index.html"},
+		{URL: "/experience/timeline", File: "experience/index.html"},
+		{URL: "/experience/timeline", File: "experience/timeline/index.html"},
+		{URL: "/skills/grid", File: "skills/index.html"},
+		{URL: "/skills/grid", File: "skills/grid/index.html"},
+		{URL: "/projects/grid", File: "projects/index.html"},
+		{URL: "/projects/grid", File: "projects/grid/index.html"},
+		{URL: "/education", File: "education/index.html"},
+		{URL: "/contact", File: "contact/index.html"},
+		{URL: "/favicon.ico", File: "favicon.ico"},
+		// Served by GitHub Pages, Netlify, Cloudflare Pages and most others
+		// for paths with no file.
+		{URL: "/404", File: "404.html", Status: 404},
+	}
+
+	categories := siteContent.Snapshot().Skills
+	filterCategories := []string{""}
+	for _, cat := range categories {
+		filterCategories = append(filterCategories, cat.Name)
+	}
+	for _, category := range filterCategories {
+		for _, proficiency := range []string{"", "expert", "advanced", "intermediate", "familiar"} {
+			params := url.Values{}
+			if category != "" {
+				params.Set("category", category)
+			}
+			if proficiency != "" {
+				params.Set("proficiency", proficiency)
+			}
+			u := "/skills/filtered"
+			if q := params.Encode(); q != "" {
+				u += "?" + q
+			}
+			pages = append(pages, export.Page{
+				URL:      u,
+				File:     "skills/filtered/" + slug(category, "all") + "-" + slug(proficiency, "all") + ".html",
+				Fragment: true,
+			})
+		}
+	}
+	for _, cat := range categories {
+		for _, skill := range cat.Skills {
+			id := strconv.Itoa(skill.ID)
+			pages = append(pages, export.Page{
+				URL:      "/skills/detail?id=" + id,
+				File:     "skills/detail/" + id + ".html",
+				Fragment: true,
+			})
+		}
+	}
+	return pages
+}
+
+// slug lowercases s and joins its runs of letters and digits with dashes,
+// returning empty for an empty result.
+func slug(s, empty string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	})
+	if len(words) == 0 {
+		return empty
+	}
+	return strings.Join(words, "-")
+}
@@ -0,0 +1,265 @@
+// Package config defines the server configuration and loads it from, in
+// increasing order of precedence: built-in defaults, an optional YAML or
+// TOML file, environment variables and command-line flags.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"portfolio/logging"
+	"portfolio/tracing"
+)
+
+// Environments accepted by Config.Env.
+const (
+	EnvProduction  = "production"
+	EnvDevelopment = "development"
+)
+
+// Config is the effective server configuration. Each field is tagged with
+// its file key (yaml/toml), environment variable (env) and flag name (flag).
+// Fields tagged secret are never printed.
+type Config struct {
+	Env      string   `yaml:"env" toml:"env" env:"APP_ENV" flag:"env" usage:"environment: production or development"`
+	Server   Server   `yaml:"server" toml:"server"`
+	Log      Log      `yaml:"log" toml:"log"`
+	Site     Site     `yaml:"site" toml:"site"`
+	Content  Content  `yaml:"content" toml:"content"`
+	Stats    Stats    `yaml:"stats" toml:"stats"`
+	Soccer   Soccer   `yaml:"soccer" toml:"soccer"`
+	Mail     Mail     `yaml:"mail" toml:"mail"`
+	Security Security `yaml:"security" toml:"security"`
+	Metrics  Metrics  `yaml:"metrics" toml:"metrics"`
+	Tracing  Tracing  `yaml:"tracing" toml:"tracing"`
+}
+
+// Server configures the HTTP listener.
+type Server struct {
+	Addr            string        `yaml:"addr" toml:"addr" env:"ADDR" flag:"addr" usage:"listen address"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"READ_TIMEOUT" flag:"read-timeout" usage:"maximum duration for reading a request"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"WRITE_TIMEOUT" flag:"write-timeout" usage:"maximum duration for writing a response"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"IDLE_TIMEOUT" flag:"idle-timeout" usage:"keep-alive idle timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"drain deadline after SIGINT/SIGTERM"`
+	StaticDir       string        `yaml:"static_dir" toml:"static_dir" env:"STATIC_DIR" flag:"static-dir" usage:"serve static assets from this directory instead of the embedded copy, for live editing"`
+}
+
+// Log configures the server logger.
+type Log struct {
+	Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL" flag:"log-level" usage:"minimum log level: debug, info, warn or error"`
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT" flag:"log-format" usage:"log format: json or text (defaults to text in development)"`
+}
+
+// Site holds profile details shown on the home, about and education pages.
+type Site struct {
+	CareerStartYear        int    `yaml:"career_start_year" toml:"career_start_year" env:"CAREER_START_YEAR" flag:"career-start-year" usage:"year used to compute years in tech"`
+	CertificationStartYear int    `yaml:"certification_start_year" toml:"certification_start_year" env:"CERTIFICATION_START_YEAR" flag:"certification-start-year" usage:"year used to compute years certifying"`
+	GravatarEmail          string `yaml:"gravatar_email" toml:"gravatar_email" env:"GRAVATAR_EMAIL" flag:"gravatar-email" usage:"email address of the Gravatar avatar"`
+}
+
+// Content locates the experience, skills and projects content files.
+type Content struct {
+	Dir string `yaml:"dir" toml:"dir" env:"CONTENT_DIR" flag:"content-dir" usage:"read content files from this directory instead of the embedded copy; missing files fall back to it"`
+}
+
+// Stats holds the counters shown in the home, about and education page stat
+// cards.
+type Stats struct {
+	Certifications         int    `yaml:"certifications" toml:"certifications" env:"STATS_CERTIFICATIONS" flag:"stats-certifications" usage:"certifications earned"`
+	CertificationProviders int    `yaml:"certification_providers" toml:"certification_providers" env:"STATS_CERTIFICATION_PROVIDERS" flag:"stats-certification-providers" usage:"organizations certifications were earned from"`
+	AutomationProjects     string `yaml:"automation_projects" toml:"automation_projects" env:"STATS_AUTOMATION_PROJECTS" flag:"stats-automation-projects" usage:"automation projects delivered"`
+	TechUsed               int    `yaml:"tech_used" toml:"tech_used" env:"STATS_TECH_USED" flag:"stats-tech-used" usage:"technologies used"`
+	CupsOfCoffee           string `yaml:"cups_of_coffee" toml:"cups_of_coffee" env:"STATS_CUPS_OF_COFFEE" flag:"stats-cups-of-coffee" usage:"cups of coffee consumed"`
+}
+
+// Soccer configures the schedule tool's background watcher and webhooks.
+type Soccer struct {
+	BaseURL            string        `yaml:"base_url" toml:"base_url" env:"SOCCER_BASE_URL" flag:"soccer-base-url" usage:"public site URL linked from notifications"`
+	WatchInterval      time.Duration `yaml:"watch_interval" toml:"watch_interval" env:"SOCCER_WATCH_INTERVAL" flag:"soccer-watch-interval" usage:"how often subscribed teams are polled"`
+	AllowLocalWebhooks bool          `yaml:"allow_local_webhooks" toml:"allow_local_webhooks" env:"SOCCER_WEBHOOK_ALLOW_LOCAL" flag:"soccer-allow-local-webhooks" usage:"accept http and private-network webhook URLs"`
+}
+
+// Mail configures the SMTP relay schedule-change emails are sent through.
+// Email subscriptions are only offered when Addr is set.
+type Mail struct {
+	Addr     string `yaml:"addr" toml:"addr" env:"SMTP_ADDR" flag:"smtp-addr" usage:"host:port of the SMTP relay for schedule-change emails; email subscriptions are off when empty"`
+	Username string `yaml:"username" toml:"username" env:"SMTP_USERNAME" flag:"smtp-username" usage:"SMTP user name; no authentication when empty"`
+	Password string `yaml:"password" toml:"password" env:"SMTP_PASSWORD" flag:"smtp-password" secret:"true" usage:"SMTP password"`
+	From     string `yaml:"from" toml:"from" env:"SMTP_FROM" flag:"smtp-from" usage:"sender address of schedule-change emails"`
+}
+
+// Security configures the browser hardening headers.
+type Security struct {
+	HSTSMaxAge    time.Duration `yaml:"hsts_max_age" toml:"hsts_max_age" env:"HSTS_MAX_AGE" flag:"hsts-max-age" usage:"Strict-Transport-Security max-age; 0 disables the header"`
+	CSPReportOnly bool          `yaml:"csp_report_only" toml:"csp_report_only" env:"CSP_REPORT_ONLY" flag:"csp-report-only" usage:"report Content-Security-Policy violations without blocking them"`
+}
+
+// Metrics configures the Prometheus endpoint.
+type Metrics struct {
+	Addr  string `yaml:"addr" toml:"addr" env:"METRICS_ADDR" flag:"metrics-addr" usage:"serve /metrics on this address only instead of the main listener"`
+	Token string `yaml:"token" toml:"token" env:"METRICS_TOKEN" flag:"metrics-token" secret:"true" usage:"bearer token required to scrape /metrics"`
+}
+
+// Tracing configures OpenTelemetry tracing. The OTLP exporter reads its
+// endpoint and headers from the standard OTEL_EXPORTER_OTLP_* variables.
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER" flag:"tracing-exporter" usage:"trace exporter: none, otlp or stdout"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" flag:"tracing-sample-ratio" usage:"fraction of new traces recorded, from 0 to 1"`
+}
+
+// Default returns the built-in configuration.
+func Default() Config {
+	return Config{
+		Env: EnvProduction,
+		Server: Server{
+			Addr:            ":8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 20 * time.Second,
+		},
+		Log: Log{Level: "info"},
+		Site: Site{
+			CareerStartYear:        2012,
+			CertificationStartYear: 2018,
+			GravatarEmail:          "gravatar@craigdevjohnson.com",
+		},
+		Stats: Stats{
+			Certifications:         10,
+			CertificationProviders: 5,
+			AutomationProjects:     "100",
+			TechUsed:               30,
+			CupsOfCoffee:           "∞",
+		},
+		Soccer: Soccer{
+			BaseURL:       "https://craigdevjohnson.com",
+			WatchInterval: 15 * time.Minute,
+		},
+		Security: Security{HSTSMaxAge: 365 * 24 * time.Hour},
+		Tracing:  Tracing{Exporter: tracing.ExporterNone, SampleRatio: 1},
+	}
+}
+
+// Development reports whether the server runs in the development environment.
+func (c Config) Development() bool {
+	return c.Env == EnvDevelopment
+}
+
+// LogOptions returns the logger options.
+func (c Config) LogOptions() (logging.Options, error) {
+	level, err := logging.ParseLevel(c.Log.Level)
+	if err != nil {
+		return logging.Options{}, err
+	}
+	return logging.Options{Format: c.Log.Format, Level: level}, nil
+}
+
+// resolve fills in defaults that depend on other settings: the log format
+// is text in development and JSON otherwise unless set explicitly.
+func (c *Config) resolve() {
+	if c.Log.Format == "" {
+		c.Log.Format = logging.FormatJSON
+		if c.Development() {
+			c.Log.Format = logging.FormatText
+		}
+	}
+}
+
+// Validate reports every invalid value, naming the offending key.
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+	}
+
+	if c.Env != EnvProduction && c.Env != EnvDevelopment {
+		fail("env", "must be %q or %q, got %q", EnvProduction, EnvDevelopment, c.Env)
+	}
+
+	if _, _, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		fail("server.addr", "must be host:port, got %q", c.Server.Addr)
+	}
+	for _, t := range []struct {
+		key string
+		d   time.Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+	} {
+		if t.d <= 0 {
+			fail(t.key, "must be a positive duration, got %s", t.d)
+		}
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		fail("log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	if f := c.Log.Format; f != logging.FormatJSON && f != logging.FormatText {
+		fail("log.format", "must be %q or %q, got %q", logging.FormatJSON, logging.FormatText, f)
+	}
+
+	if y := c.Site.CareerStartYear; y < 1970 || y > time.Now().Year() {
+		fail("site.career_start_year", "must be between 1970 and the current year, got %d", y)
+	}
+	if y := c.Site.CertificationStartYear; y < 1970 || y > time.Now().Year() {
+		fail("site.certification_start_year", "must be between 1970 and the current year, got %d", y)
+	}
+	if _, err := mail.ParseAddress(c.Site.GravatarEmail); err != nil {
+		fail("site.gravatar_email", "must be an email address, got %q", c.Site.GravatarEmail)
+	}
+
+	if c.Stats.Certifications < 0 {
+		fail("stats.certifications", "must not be negative, got %d", c.Stats.Certifications)
+	}
+	if c.Stats.CertificationProviders < 0 {
+		fail("stats.certification_providers", "must not be negative, got %d", c.Stats.CertificationProviders)
+	}
+	if c.Stats.TechUsed < 0 {
+		fail("stats.tech_used", "must not be negative, got %d", c.Stats.TechUsed)
+	}
+
+	if u, err := url.Parse(c.Soccer.BaseURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		fail("soccer.base_url", "must be an absolute http(s) URL, got %q", c.Soccer.BaseURL)
+	}
+	if c.Soccer.WatchInterval < time.Minute {
+		fail("soccer.watch_interval", "must be at least 1m, got %s", c.Soccer.WatchInterval)
+	}
+
+	if c.Mail.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Mail.Addr); err != nil {
+			fail("mail.addr", "must be host:port, got %q", c.Mail.Addr)
+		}
+		if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+			fail("mail.from", "must be an email address when mail.addr is set, got %q", c.Mail.From)
+		}
+	}
+
+	if c.Security.HSTSMaxAge < 0 {
+		fail("security.hsts_max_age", "must not be negative, got %s", c.Security.HSTSMaxAge)
+	}
+
+	if c.Metrics.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Metrics.Addr); err != nil {
+			fail("metrics.addr", "must be host:port, got %q", c.Metrics.Addr)
+		} else if c.Metrics.Addr == c.Server.Addr {
+			fail("metrics.addr", "must differ from server.addr")
+		}
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	default:
+		fail("tracing.exporter", "must be %q, %q or %q, got %q", tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout, c.Tracing.Exporter)
+	}
+	if r := c.Tracing.SampleRatio; r < 0 || r > 1 {
+		fail("tracing.sample_ratio", "must be between 0 and 1, got %g", r)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Flags holds the command-line options that control loading itself rather
+// than the server.
+type Flags struct {
+	File        string // config file read between defaults and the environment
+	PrintConfig bool   // print the effective configuration and exit
+}
+
+// Load builds the configuration from defaults, then the config file named
+// by --config or CONFIG_FILE, then environment variables, then the flags in
+// args. The result is validated before it is returned. flag.ErrHelp is
+// returned when args ask for usage.
+func Load(args []string, getenv func(string) string) (Config, Flags, error) {
+	cfg := Default()
+	fields := leafFields(reflect.ValueOf(&cfg).Elem())
+
+	var flags Flags
+	fs := flag.NewFlagSet("portfolio", flag.ContinueOnError)
+	fs.StringVar(&flags.File, "config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	fs.BoolVar(&flags.PrintConfig, "print-config", false, "print the effective configuration as YAML and exit")
+
+	// Flag values are applied after the file and environment, so parsing
+	// only records them.
+	type setting struct {
+		field field
+		value string
+	}
+	var fromFlags []setting
+	for _, f := range fields {
+		usage := fmt.Sprintf("%s (env %s)", f.usage, f.env)
+		if def := f.value.Interface(); !f.value.IsZero() {
+			usage += fmt.Sprintf(" (default %v)", def)
+		}
+		record := func(s string) error {
+			if err := f.set(s); err != nil { // reject bad values at parse time
+				return err
+			}
+			fromFlags = append(fromFlags, setting{f, s})
+			return nil
+		}
+		if f.value.Kind() == reflect.Bool {
+			fs.BoolFunc(f.flag, usage, record)
+		} else {
+			fs.Func(f.flag, usage, record)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, Flags{}, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, Flags{}, fmt.Errorf("config: unexpected argument %q", fs.Arg(0))
+	}
+	// Recording flags wrote straight into cfg; start again from defaults.
+	cfg = Default()
+
+	if flags.File == "" {
+		flags.File = getenv("CONFIG_FILE")
+	}
+	if flags.File != "" {
+		if err := decodeFile(flags.File, &cfg); err != nil {
+			return Config{}, Flags{}, err
+		}
+	}
+	for _, f := range fields {
+		if raw := getenv(f.env); raw != "" {
+			if err := f.set(raw); err != nil {
+				return Config{}, Flags{}, fmt.Errorf("config: %s: %w", f.env, err)
+			}
+		}
+	}
+	for _, s := range fromFlags {
+		if err := s.field.set(s.value); err != nil {
+			return Config{}, Flags{}, fmt.Errorf("config: -%s: %w", s.field.flag, err)
+		}
+	}
+
+	cfg.resolve()
+	if err := cfg.Validate(); err != nil {
+		return Config{}, Flags{}, fmt.Errorf("config: invalid configuration:\n%w", err)
+	}
+	return cfg, flags, nil
+}
+
+// Redacted replaces the value of set secret fields when printing.
+const Redacted = "<redacted>"
+
+// Write prints cfg as YAML in the config file format, with every secret
+// that is set replaced by Redacted.
+func Write(w io.Writer, cfg Config) error {
+	for _, f := range leafFields(reflect.ValueOf(&cfg).Elem()) {
+		if f.secret && !f.value.IsZero() {
+			f.value.SetString(Redacted)
+		}
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// decodeFile overlays the file at path onto cfg. Keys that do not map to a
+// field are rejected so typos are not silently ignored.
+func decodeFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("config: %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("config: %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("config: %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("config: %s: unsupported file type %q (want .yaml, .yml or .toml)", path, ext)
+	}
+	return nil
+}
+
+// field is a settable leaf of Config together with its tags.
+type field struct {
+	value  reflect.Value
+	env    string
+	flag   string
+	usage  string
+	secret bool // string holding a credential
+}
+
+// leafFields returns the tagged leaf fields of the struct v, descending into
+// nested structs.
+func leafFields(v reflect.Value) []field {
+	var fields []field
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, leafFields(fv)...)
+			continue
+		}
+		fields = append(fields, field{
+			value:  fv,
+			env:    sf.Tag.Get("env"),
+			flag:   sf.Tag.Get("flag"),
+			usage:  sf.Tag.Get("usage"),
+			secret: sf.Tag.Get("secret") == "true",
+		})
+	}
+	return fields
+}
+
+// set parses s according to the field's type and stores it.
+func (f field) set(s string) error {
+	switch f.value.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(d))
+	case string:
+		f.value.SetString(s)
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		f.value.SetInt(int64(n))
+	case float64:
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		f.value.SetFloat(x)
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		f.value.SetBool(b)
+	default:
+		panic("config: unsupported field type " + f.value.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, flags, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Default()
+	want.resolve()
+	if cfg != want {
+		t.Errorf("Load() = %+v, want the defaults %+v", cfg, want)
+	}
+	if flags != (Flags{}) {
+		t.Errorf("flags = %+v, want none", flags)
+	}
+	if cfg.Log.Format != "json" {
+		t.Errorf("production log format = %q, want json", cfg.Log.Format)
+	}
+}
+
+// Each source overrides the ones before it: defaults, file, environment,
+// flags.
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+server:
+  addr: ":9000"
+  read_timeout: 5s
+log:
+  level: debug
+stats:
+  tech_used: 40
+`)
+	cfg, flags, err := Load(
+		[]string{"--config", file, "--addr", ":9200"},
+		env(map[string]string{"ADDR": ":9100", "LOG_LEVEL": "warn", "APP_ENV": "development"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags.File != file {
+		t.Errorf("flags.File = %q, want %q", flags.File, file)
+	}
+	checks := []struct {
+		key       string
+		got, want any
+	}{
+		{"server.addr (flag over env and file)", cfg.Server.Addr, ":9200"},
+		{"log.level (env over file)", cfg.Log.Level, "warn"},
+		{"server.read_timeout (file over default)", cfg.Server.ReadTimeout, 5 * time.Second},
+		{"stats.tech_used (file over default)", cfg.Stats.TechUsed, 40},
+		{"server.write_timeout (default)", cfg.Server.WriteTimeout, 15 * time.Second},
+		{"log.format (resolved from env)", cfg.Log.Format, "text"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.key, c.got, c.want)
+		}
+	}
+}
+
+func TestLoadConfigFileFromEnv(t *testing.T) {
+	file := writeFile(t, "config.toml", "[server]\naddr = \":9300\"\n")
+	cfg, flags, err := Load(nil, env(map[string]string{"CONFIG_FILE": file}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr != ":9300" || flags.File != file {
+		t.Errorf("server.addr = %q, file = %q; want :9300 from %s", cfg.Server.Addr, flags.File, file)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{"unknown yaml key", []string{"--config", writeFile(t, "c.yaml", "server:\n  adr: x\n")}, nil, "adr"},
+		{"unknown toml key", []string{"--config", writeFile(t, "c.toml", "[server]\nadr = \"x\"\n")}, nil, "server.adr"},
+		{"unsupported file type", []string{"--config", writeFile(t, "c.json", "{}")}, nil, "unsupported file type"},
+		{"bad env value", nil, map[string]string{"READ_TIMEOUT": "soon"}, "READ_TIMEOUT"},
+		{"bad flag value", []string{"--stats-tech-used", "many"}, nil, "invalid integer"},
+		{"positional argument", []string{"serve"}, nil, `unexpected argument "serve"`},
+		{"invalid value", []string{"--log-level", "loud"}, nil, "log.level: must be"},
+		{"every invalid value", []string{"--env", "staging", "--soccer-watch-interval", "1s"}, nil, "soccer.watch_interval"},
+		{"future certification year", []string{"--certification-start-year", "3000"}, nil, "site.certification_start_year: must be"},
+		{"negative providers", nil, map[string]string{"STATS_CERTIFICATION_PROVIDERS": "-1"}, "stats.certification_providers: must not be negative"},
+		{"mail without sender", nil, map[string]string{"SMTP_ADDR": "smtp.example.com:587"}, "mail.from: must be an email address"},
+	}
+	for _, tt := range tests {
+		_, _, err := Load(tt.args, env(tt.env))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestLoadHelp(t *testing.T) {
+	_, _, err := Load([]string{"--help"}, env(nil))
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("error = %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestWriteRedactsSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Metrics.Token = "s3cret-token"
+
+	var buf bytes.Buffer
+	if err := Write(&buf, cfg); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "s3cret-token") {
+		t.Errorf("Write printed the metrics token:\n%s", out)
+	}
+	if !strings.Contains(out, "token: <redacted>") {
+		t.Errorf("Write did not mark the token as redacted:\n%s", out)
+	}
+	if cfg.Metrics.Token != "s3cret-token" {
+		t.Error("Write modified its argument")
+	}
+
+	// An unset secret is printed as empty, so the output still shows that
+	// none is configured.
+	buf.Reset()
+	if err := Write(&buf, Default()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), Redacted) {
+		t.Errorf("Write redacted an unset token:\n%s", buf.String())
+	}
+}
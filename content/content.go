@@ -0,0 +1,129 @@
+// Package content loads the experience, skills and projects shown on the
+// site from versioned YAML or JSON files. The files next to this source
+// are embedded in the binary; a content directory holding files of the
+// same names overrides them without a rebuild.
+package content
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"portfolio/types"
+)
+
+//go:embed *.yaml
+var embedded embed.FS
+
+// Version is the content file format this build reads. Every file carries
+// it in a top-level version key, so files written for a newer format are
+// rejected instead of being half understood.
+const Version = 1
+
+// Base names of the content files. Each may end in .yaml, .yml or .json.
+const (
+	ExperienceFile = "experience"
+	SkillsFile     = "skills"
+	ProjectsFile   = "projects"
+)
+
+var extensions = []string{".yaml", ".yml", ".json"}
+
+// Content is one snapshot of the site content. Snapshots are shared by
+// concurrent requests and must not be modified.
+type Content struct {
+	Experience []types.Experience
+	Skills     []types.SkillCategory
+	Projects   []types.Project
+	// Technologies are names experience and projects may list without a
+	// skill card, such as products too narrow to feature on /skills.
+	Technologies []string
+}
+
+type experienceFile struct {
+	Version    int                `yaml:"version"`
+	Experience []types.Experience `yaml:"experience"`
+}
+
+type skillsFile struct {
+	Version      int                   `yaml:"version"`
+	Categories   []types.SkillCategory `yaml:"categories"`
+	Technologies []string              `yaml:"technologies"`
+}
+
+type projectsFile struct {
+	Version  int             `yaml:"version"`
+	Projects []types.Project `yaml:"projects"`
+}
+
+// Load reads the content files from dir, falling back to the embedded copy
+// of any file dir does not have. An empty dir loads the embedded files.
+func Load(dir string) (*Content, error) {
+	var sources []fs.FS
+	if dir != "" {
+		// A missing directory would otherwise quietly serve the embedded
+		// content.
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("content: %s is not a directory", dir)
+		}
+		sources = append(sources, os.DirFS(dir))
+	}
+	sources = append(sources, embedded)
+
+	var (
+		experience experienceFile
+		skills     skillsFile
+		projects   projectsFile
+	)
+	err := errors.Join(
+		decode(sources, ExperienceFile, &experience, &experience.Version),
+		decode(sources, SkillsFile, &skills, &skills.Version),
+		decode(sources, ProjectsFile, &projects, &projects.Version),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Content{
+		Experience:   experience.Experience,
+		Skills:       skills.Categories,
+		Projects:     projects.Projects,
+		Technologies: skills.Technologies,
+	}, nil
+}
+
+// decode finds the content file base in the first source that has it and
+// decodes it into v, checking the version it declares. JSON is decoded as
+// the YAML subset it is.
+func decode(sources []fs.FS, base string, v any, version *int) error {
+	for _, fsys := range sources {
+		for _, ext := range extensions {
+			name := base + ext
+			data, err := fs.ReadFile(fsys, name)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("content: %w", err)
+			}
+			dec := yaml.NewDecoder(bytes.NewReader(data))
+			dec.KnownFields(true) // catch misspelled keys
+			if err := dec.Decode(v); err != nil {
+				return fmt.Errorf("content: %s: %w", name, err)
+			}
+			switch *version {
+			case Version:
+				return nil
+			case 0:
+				return fmt.Errorf("content: %s: missing version (want %d)", name, Version)
+			default:
+				return fmt.Errorf("content: %s: unsupported version %d (this build reads %d)", name, *version, Version)
+			}
+		}
+	}
+	return fmt.Errorf("content: no %s file", base)
+}
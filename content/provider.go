@@ -0,0 +1,140 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"portfolio/logging"
+)
+
+// reloadDelay lets a burst of file events, such as an editor writing a
+// temporary file and renaming it over the original, settle into one reload.
+const reloadDelay = 250 * time.Millisecond
+
+// Provider holds the current content snapshot. Snapshots are immutable and
+// replaced as a whole, so a handler that takes one sees consistent content
+// for the rest of the request while reloads happen concurrently.
+type Provider struct {
+	dir     string
+	static  fs.FS // checked for icon and image paths
+	current atomic.Pointer[Content]
+}
+
+// NewProvider loads and validates the initial snapshot from dir, or from
+// the embedded files when dir is empty.
+func NewProvider(dir string, static fs.FS) (*Provider, error) {
+	p := &Provider{dir: dir, static: static}
+	c, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	p.current.Store(c)
+	return p, nil
+}
+
+func (p *Provider) load() (*Content, error) {
+	c, err := Load(p.dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Validate(p.static); err != nil {
+		return nil, fmt.Errorf("content: invalid content:\n%w", err)
+	}
+	return c, nil
+}
+
+// Snapshot returns the current content. Callers must not modify it.
+func (p *Provider) Snapshot() *Content {
+	return p.current.Load()
+}
+
+// Reload reparses and validates the content files and swaps in the result.
+// On error the previous snapshot stays in place.
+func (p *Provider) Reload() error {
+	c, err := p.load()
+	if err != nil {
+		return err
+	}
+	p.current.Store(c)
+	return nil
+}
+
+// Watch reloads the content on SIGHUP and, when the provider reads a
+// content directory, whenever a content file in it is written, created,
+// renamed or removed. A removed file falls back to its embedded copy.
+// Failed reloads are logged and the previous snapshot is kept. Watch
+// returns when ctx is done.
+func (p *Provider) Watch(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var events <-chan fsnotify.Event
+	var watchErrs <-chan error
+	if p.dir != "" {
+		watcher, err := fsnotify.NewWatcher()
+		if err == nil {
+			err = watcher.Add(p.dir)
+		}
+		if err != nil {
+			logger.Error("content: cannot watch directory; reload with SIGHUP", "dir", p.dir, "err", err)
+		} else {
+			defer watcher.Close()
+			events, watchErrs = watcher.Events, watcher.Errors
+		}
+	}
+
+	// pending fires reloadDelay after the last relevant file event.
+	pending := time.NewTimer(time.Hour)
+	pending.Stop()
+	defer pending.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			p.reload(logger, "signal")
+		case ev := <-events:
+			if isContentFile(filepath.Base(ev.Name)) {
+				pending.Reset(reloadDelay)
+			}
+		case err := <-watchErrs:
+			logger.Warn("content: watch error", "dir", p.dir, "err", err)
+		case <-pending.C:
+			p.reload(logger, "file change")
+		}
+	}
+}
+
+func (p *Provider) reload(logger *slog.Logger, trigger string) {
+	if err := p.Reload(); err != nil {
+		logger.Error("content: reload failed, keeping the previous content", "trigger", trigger, "err", err)
+		return
+	}
+	logger.Info("content reloaded", "trigger", trigger, "dir", p.dir)
+}
+
+// isContentFile reports whether name is one Load reads.
+func isContentFile(name string) bool {
+	for _, base := range []string{ExperienceFile, SkillsFile, ProjectsFile} {
+		for _, ext := range extensions {
+			if name == base+ext {
+				return true
+			}
+		}
+	}
+	return false
+}
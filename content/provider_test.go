@@ -0,0 +1,157 @@
+package content
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"portfolio/logging"
+)
+
+// projectsYAML is a projects file naming one project, valid against the
+// embedded skills and the real static directory.
+func projectsYAML(name string) string {
+	return `version: 1
+projects:
+  - id: 1
+    name: ` + name + `
+    technologies: [Go]
+    image: /static/images/projects/portfolio.webp
+    category: Web
+`
+}
+
+func TestProviderReload(t *testing.T) {
+	static := os.DirFS("../static")
+	tests := []struct {
+		name     string
+		projects string // replaces projects.yaml before Reload
+		wantErr  string
+		wantName string // first project after Reload
+	}{
+		{"valid change", projectsYAML("Renamed"), "", "Renamed"},
+		{"parse failure", "version: 1\nprojects: [", "projects.yaml", "Original"},
+		{"unknown field", projectsYAML("Original") + "    colour: blue\n", "field colour not found", "Original"},
+		{"validation failure", strings.Replace(projectsYAML("Broken"), "[Go]", "[Cobol]", 1), `unknown skill "Cobol"`, "Original"},
+		{"unsupported version", strings.Replace(projectsYAML("Future"), "version: 1", "version: 2", 1), "unsupported version 2", "Original"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "projects.yaml")
+			if err := os.WriteFile(path, []byte(projectsYAML("Original")), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			p, err := NewProvider(dir, static)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := p.Snapshot()
+
+			if err := os.WriteFile(path, []byte(tt.projects), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err = p.Reload()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Reload() = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Reload() = %v, want an error containing %q", err, tt.wantErr)
+			}
+
+			after := p.Snapshot()
+			if got := after.Projects[0].Name; got != tt.wantName {
+				t.Errorf("project name after Reload = %q, want %q", got, tt.wantName)
+			}
+			if tt.wantErr != "" && after != before {
+				t.Error("failed Reload replaced the snapshot")
+			}
+			// Snapshots are swapped whole; one taken earlier never changes.
+			if got := before.Projects[0].Name; got != "Original" {
+				t.Errorf("earlier snapshot changed to %q", got)
+			}
+		})
+	}
+}
+
+// Files missing from the content directory come from the embedded copies,
+// including when one is removed after startup.
+func TestProviderFallsBackToEmbedded(t *testing.T) {
+	embeddedContent, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "projects.yaml")
+	if err := os.WriteFile(path, []byte(projectsYAML("Local")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewProvider(dir, os.DirFS("../static"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := p.Snapshot()
+	if len(c.Projects) != 1 || c.Projects[0].Name != "Local" {
+		t.Errorf("projects = %+v, want the local file", c.Projects)
+	}
+	if len(c.Skills) != len(embeddedContent.Skills) || len(c.Experience) != len(embeddedContent.Experience) {
+		t.Error("skills and experience do not come from the embedded files")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(p.Snapshot().Projects), len(embeddedContent.Projects); got != want {
+		t.Errorf("%d projects after removing the local file, want the %d embedded", got, want)
+	}
+}
+
+func TestProviderWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "projects.yaml")
+	if err := os.WriteFile(path, []byte(projectsYAML("Original")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewProvider(dir, os.DirFS("../static"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(logging.NewContext(t.Context(), slog.New(slog.NewTextHandler(io.Discard, nil))))
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		p.Watch(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+
+	// Keep rewriting until the watcher, which may still be starting, sees it.
+	deadline := time.Now().Add(5 * time.Second)
+	for p.Snapshot().Projects[0].Name != "Watched" {
+		if time.Now().After(deadline) {
+			t.Fatal("Watch did not reload the changed file")
+		}
+		if err := os.WriteFile(path, []byte(projectsYAML("Watched")), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * reloadDelay)
+	}
+}
+
+func TestNewProviderMissingDir(t *testing.T) {
+	if _, err := NewProvider(filepath.Join(t.TempDir(), "missing"), os.DirFS("../static")); err == nil {
+		t.Error("NewProvider with a missing directory succeeded, want an error")
+	}
+}
@@ -0,0 +1,193 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"slices"
+	"strings"
+
+	"portfolio/assets"
+)
+
+// Allowed values of the content enums. The page templates and scripts match
+// on these exact strings, so a typo would silently drop an entry from a
+// filter.
+var (
+	// Proficiencies are the Skill.Proficiency levels, highest first.
+	Proficiencies = []string{"expert", "advanced", "intermediate", "familiar"}
+	// Sides are the Experience.Side timeline positions.
+	Sides = []string{"left", "right"}
+	// SkillAreas are the keys Experience.SkillAreas may list, matched by
+	// the experience page's skill area filter.
+	SkillAreas = []string{"cloud", "automation", "systems", "security", "devops", "scripting"}
+	// ProjectCategories are the Project.Category values the projects page
+	// filter offers.
+	ProjectCategories = []string{"Web", "Automation"}
+)
+
+// Violation is one invalid value in the content, located by file, entry ID
+// and field.
+type Violation struct {
+	File    string // content file base name, e.g. SkillsFile
+	ID      int    // ID of the offending entry
+	Name    string // entry name, for readability
+	Field   string // file key of the offending field
+	Problem string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s: id %d (%s): %s: %s", v.File, v.ID, v.Name, v.Field, v.Problem)
+}
+
+// Validate reports every violation found by Check, joined into one error.
+func (c *Content) Validate(static fs.FS) error {
+	var errs []error
+	for _, v := range c.Check(static) {
+		errs = append(errs, v)
+	}
+	return errors.Join(errs...)
+}
+
+// Check returns every violation in c, in file order. static is the static
+// file tree icon and image paths are looked up in.
+func (c *Content) Check(static fs.FS) []Violation {
+	var violations []Violation
+	fail := func(file string, id int, name, field, format string, args ...any) {
+		violations = append(violations, Violation{File: file, ID: id, Name: name, Field: field, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	known := slices.Clone(c.Technologies)
+	for _, cat := range c.Skills {
+		for _, s := range cat.Skills {
+			known = append(known, s.Name)
+		}
+	}
+
+	seen := make(map[int]bool)
+	for _, e := range c.Experience {
+		report := func(field, format string, args ...any) {
+			fail(ExperienceFile, e.ID, e.Position, field, format, args...)
+		}
+		checkID(seen, e.ID, report)
+		if !slices.Contains(Sides, e.Side) {
+			report("side", "must be %s, got %q", oneOf(Sides), e.Side)
+		}
+		for area := range strings.SplitSeq(e.SkillAreas, ",") {
+			if !slices.Contains(SkillAreas, area) {
+				report("skill_areas", "unknown skill area %q, want %s", area, oneOf(SkillAreas))
+			}
+		}
+		checkTechnologies(known, e.Technologies, report)
+	}
+
+	clear(seen)
+	for _, cat := range c.Skills {
+		for _, s := range cat.Skills {
+			report := func(field, format string, args ...any) {
+				fail(SkillsFile, s.ID, s.Name, field, format, args...)
+			}
+			checkID(seen, s.ID, report)
+			if !slices.Contains(Proficiencies, s.Proficiency) {
+				report("proficiency", "must be %s, got %q", oneOf(Proficiencies), s.Proficiency)
+			}
+			if s.IconPath != "" {
+				checkStatic(static, s.IconPath, "icon_path", report)
+			} else if s.Icon == "" {
+				report("icon_path", "an icon_path or inline icon is required")
+			}
+			if s.Link != "" {
+				checkURL(s.Link, false, "link", report)
+			}
+		}
+	}
+
+	clear(seen)
+	for _, p := range c.Projects {
+		report := func(field, format string, args ...any) {
+			fail(ProjectsFile, p.ID, p.Name, field, format, args...)
+		}
+		checkID(seen, p.ID, report)
+		if !slices.Contains(ProjectCategories, p.Category) {
+			report("category", "must be %s, got %q", oneOf(ProjectCategories), p.Category)
+		}
+		checkStatic(static, p.Image, "image", report)
+		if p.GitHubURL != "" {
+			checkURL(p.GitHubURL, false, "github_url", report)
+		}
+		if p.DemoURL != "" {
+			checkURL(p.DemoURL, true, "demo_url", report)
+		}
+		checkTechnologies(known, p.Technologies, report)
+	}
+
+	return violations
+}
+
+type failFunc func(field, format string, args ...any)
+
+// checkID reports an ID that is not positive or was already seen in the
+// same file.
+func checkID(seen map[int]bool, id int, fail failFunc) {
+	switch {
+	case id <= 0:
+		fail("id", "must be a positive number")
+	case seen[id]:
+		fail("id", "used by more than one entry")
+	}
+	seen[id] = true
+}
+
+// checkTechnologies reports technologies that name neither a skill nor an
+// entry of the skills file's technologies list.
+func checkTechnologies(known, technologies []string, fail failFunc) {
+	for _, tech := range technologies {
+		if !slices.Contains(known, tech) {
+			fail("technologies", "unknown skill %q; add it to %s or its technologies list", tech, SkillsFile)
+		}
+	}
+}
+
+// checkStatic reports a path that is not a file under assets.Prefix.
+func checkStatic(static fs.FS, p, field string, fail failFunc) {
+	name, ok := strings.CutPrefix(p, assets.Prefix)
+	if !ok {
+		fail(field, "must be a path under %s, got %q", assets.Prefix, p)
+		return
+	}
+	if info, err := fs.Stat(static, name); err != nil || info.IsDir() {
+		fail(field, "static file %s does not exist", p)
+	}
+}
+
+// checkURL reports a link that is not an absolute http(s) URL or, when
+// local is set, a path on this site.
+func checkURL(raw string, local bool, field string, fail failFunc) {
+	u, err := url.Parse(raw)
+	switch {
+	case err != nil:
+		fail(field, "malformed URL %q", raw)
+	case u.Scheme == "https" || u.Scheme == "http":
+		if u.Host == "" {
+			fail(field, "URL %q has no host", raw)
+		}
+	case local && u.Scheme == "" && u.Host == "" && strings.HasPrefix(u.Path, "/"):
+	case local:
+		fail(field, "must be an absolute http(s) URL or a path starting with /, got %q", raw)
+	default:
+		fail(field, "must be an absolute http(s) URL, got %q", raw)
+	}
+}
+
+// oneOf formats allowed values for a message: "a", "b" or "c".
+func oneOf(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return "one of " + strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
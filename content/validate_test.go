@@ -0,0 +1,147 @@
+package content
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"portfolio/types"
+)
+
+var testStatic = fstest.MapFS{
+	"images/skills/go.svg":         {Data: []byte("<svg/>")},
+	"images/projects/scraper.webp": {Data: []byte("webp")},
+}
+
+// validContent returns content that passes Check against testStatic.
+func validContent() *Content {
+	return &Content{
+		Experience: []types.Experience{
+			{ID: 1, Position: "Engineer", Technologies: []string{"Go", "SCCM"}, SkillAreas: "cloud,automation", Side: "left"},
+		},
+		Skills: []types.SkillCategory{{Name: "Languages", Skills: []types.Skill{
+			{ID: 1, Name: "Go", IconPath: "/static/images/skills/go.svg", Link: "https://go.dev/", Proficiency: "expert"},
+			{ID: 2, Name: "Bash", Icon: "<svg/>", Proficiency: "familiar"},
+		}}},
+		Projects: []types.Project{
+			{ID: 1, Name: "Scraper", Category: "Automation", Image: "/static/images/projects/scraper.webp", Technologies: []string{"Go"}, GitHubURL: "https://github.com/example/scraper", DemoURL: "/soccer"},
+		},
+		Technologies: []string{"SCCM"},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Content)
+		want   []string
+	}{
+		{"valid", func(c *Content) {}, nil},
+		{"duplicate experience id", func(c *Content) {
+			c.Experience = append(c.Experience, types.Experience{ID: 1, Position: "Intern", SkillAreas: "cloud", Side: "right"})
+		}, []string{`experience: id 1 (Intern): id: used by more than one entry`}},
+		{"duplicate skill id across categories", func(c *Content) {
+			c.Skills = append(c.Skills, types.SkillCategory{Name: "Shells", Skills: []types.Skill{{ID: 2, Name: "Zsh", Icon: "<svg/>", Proficiency: "familiar"}}})
+		}, []string{`skills: id 2 (Zsh): id: used by more than one entry`}},
+		{"ids are per file", func(c *Content) {
+			c.Projects[0].ID = 2
+		}, nil},
+		{"non-positive id", func(c *Content) {
+			c.Projects[0].ID = 0
+		}, []string{`projects: id 0 (Scraper): id: must be a positive number`}},
+		{"bad proficiency", func(c *Content) {
+			c.Skills[0].Skills[0].Proficiency = "Expert"
+		}, []string{`skills: id 1 (Go): proficiency: must be one of "expert", "advanced", "intermediate" or "familiar", got "Expert"`}},
+		{"bad side", func(c *Content) {
+			c.Experience[0].Side = "center"
+		}, []string{`experience: id 1 (Engineer): side: must be one of "left" or "right", got "center"`}},
+		{"bad skill area", func(c *Content) {
+			c.Experience[0].SkillAreas = "cloud, networking"
+		}, []string{
+			`experience: id 1 (Engineer): skill_areas: unknown skill area " networking", want one of "cloud", "automation", "systems", "security", "devops" or "scripting"`,
+		}},
+		{"bad category", func(c *Content) {
+			c.Projects[0].Category = "web"
+		}, []string{`projects: id 1 (Scraper): category: must be one of "Web" or "Automation", got "web"`}},
+		{"missing icon file", func(c *Content) {
+			c.Skills[0].Skills[0].IconPath = "/static/images/skills/golang.svg"
+		}, []string{`skills: id 1 (Go): icon_path: static file /static/images/skills/golang.svg does not exist`}},
+		{"icon outside static", func(c *Content) {
+			c.Skills[0].Skills[0].IconPath = "https://cdn.example.com/go.svg"
+		}, []string{`skills: id 1 (Go): icon_path: must be a path under /static/, got "https://cdn.example.com/go.svg"`}},
+		{"no icon", func(c *Content) {
+			c.Skills[0].Skills[1].Icon = ""
+		}, []string{`skills: id 2 (Bash): icon_path: an icon_path or inline icon is required`}},
+		{"image is a directory", func(c *Content) {
+			c.Projects[0].Image = "/static/images/projects"
+		}, []string{`projects: id 1 (Scraper): image: static file /static/images/projects does not exist`}},
+		{"malformed link", func(c *Content) {
+			c.Skills[0].Skills[0].Link = "https://go.dev/%zz"
+		}, []string{`skills: id 1 (Go): link: malformed URL "https://go.dev/%zz"`}},
+		{"relative link", func(c *Content) {
+			c.Skills[0].Skills[0].Link = "go.dev"
+		}, []string{`skills: id 1 (Go): link: must be an absolute http(s) URL, got "go.dev"`}},
+		{"link without host", func(c *Content) {
+			c.Projects[0].GitHubURL = "https:///example/scraper"
+		}, []string{`projects: id 1 (Scraper): github_url: URL "https:///example/scraper" has no host`}},
+		{"unsafe demo url", func(c *Content) {
+			c.Projects[0].DemoURL = "javascript:alert(1)"
+		}, []string{`projects: id 1 (Scraper): demo_url: must be an absolute http(s) URL or a path starting with /, got "javascript:alert(1)"`}},
+		{"unknown experience technology", func(c *Content) {
+			c.Experience[0].Technologies = append(c.Experience[0].Technologies, "Golang")
+		}, []string{`experience: id 1 (Engineer): technologies: unknown skill "Golang"; add it to skills or its technologies list`}},
+		{"unknown project technology", func(c *Content) {
+			c.Projects[0].Technologies = []string{"go", "Intune"}
+		}, []string{
+			`projects: id 1 (Scraper): technologies: unknown skill "go"; add it to skills or its technologies list`,
+			`projects: id 1 (Scraper): technologies: unknown skill "Intune"; add it to skills or its technologies list`,
+		}},
+		{"violations in file order", func(c *Content) {
+			c.Projects[0].Category = ""
+			c.Experience[0].Side = ""
+		}, []string{
+			`experience: id 1 (Engineer): side: must be one of "left" or "right", got ""`,
+			`projects: id 1 (Scraper): category: must be one of "Web" or "Automation", got ""`,
+		}},
+	}
+	for _, tt := range tests {
+		c := validContent()
+		tt.modify(c)
+		var got []string
+		for _, v := range c.Check(testStatic) {
+			got = append(got, v.Error())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validContent().Validate(testStatic); err != nil {
+		t.Errorf("valid content: %v", err)
+	}
+
+	c := validContent()
+	c.Skills[0].Skills[0].Proficiency = ""
+	c.Projects[0].Technologies = []string{"Rust"}
+	err := c.Validate(testStatic)
+	want := `skills: id 1 (Go): proficiency: must be one of "expert", "advanced", "intermediate" or "familiar", got ""` + "\n" +
+		`projects: id 1 (Scraper): technologies: unknown skill "Rust"; add it to skills or its technologies list`
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want\n%s", err, want)
+	}
+}
+
+// TestEmbeddedContent checks the content shipped in the binary, as the
+// server does at startup.
+func TestEmbeddedContent(t *testing.T) {
+	c, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range c.Check(os.DirFS("../static")) {
+		t.Error(v)
+	}
+}
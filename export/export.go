@@ -0,0 +1,143 @@
+// Package export renders the site to a directory of static files that any
+// static host can serve. Pages are rendered in-process through the server's
+// own handler, so they match what the server sends, and links that only the
+// server can answer, such as htmx fragments selected by query string, are
+// rewritten to the files they were exported to.
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"portfolio/assets"
+)
+
+// RedirectsFile is written in the format read by Netlify and Cloudflare
+// Pages: one "from to status" rule per line.
+const RedirectsFile = "_redirects"
+
+// Page is one URL to render into a file.
+type Page struct {
+	URL      string // request path and query, e.g. "/skills/detail?id=3"
+	File     string // output path, relative to the output directory
+	Fragment bool   // requested as htmx does; links to URL are rewritten to File
+	Status   int    // expected response status; 0 means 200
+}
+
+// Redirect is a rule for the host's redirects file.
+type Redirect struct {
+	From   string
+	To     string
+	Status int
+}
+
+// Site describes an export.
+type Site struct {
+	Handler   http.Handler
+	Static    *assets.Manifest // fingerprinting manifest the pages were rendered with
+	Pages     []Page
+	Redirects []Redirect
+	// Links maps link targets to their replacements in exported HTML, e.g.
+	// a path served by another origin. Fragment pages are added to it.
+	Links map[string]string
+}
+
+// Write renders every page, copies the static tree under
+// assets.Prefix and writes the redirects file into dir, creating it if
+// needed. Existing files are overwritten; others are left alone. A line per
+// file written is reported to out.
+func (s Site) Write(dir string, out io.Writer) error {
+	links := maps.Clone(s.Links)
+	if links == nil {
+		links = make(map[string]string)
+	}
+	for _, p := range s.Pages {
+		if p.Fragment {
+			links[p.URL] = "/" + filepath.ToSlash(p.File)
+		}
+	}
+	rewrite := rewriter(links)
+
+	for _, p := range s.Pages {
+		body, isHTML, err := s.render(p)
+		if err != nil {
+			return err
+		}
+		if isHTML {
+			body = []byte(rewrite.Replace(string(body)))
+		}
+		if err := writeFile(filepath.Join(dir, p.File), body); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s -> %s\n", p.URL, p.File)
+	}
+
+	if err := s.Static.Copy(filepath.Join(dir, strings.Trim(assets.Prefix, "/"))); err != nil {
+		return fmt.Errorf("copying static files: %w", err)
+	}
+	fmt.Fprintf(out, "static files -> %s\n", strings.Trim(assets.Prefix, "/"))
+
+	if len(s.Redirects) > 0 {
+		var buf bytes.Buffer
+		for _, r := range s.Redirects {
+			fmt.Fprintf(&buf, "%s %s %d\n", r.From, r.To, r.Status)
+		}
+		if err := writeFile(filepath.Join(dir, RedirectsFile), buf.Bytes()); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%d redirects -> %s\n", len(s.Redirects), RedirectsFile)
+	}
+	return nil
+}
+
+// render requests p from the handler and returns the body and whether it
+// is HTML.
+func (s Site) render(p Page) (body []byte, isHTML bool, err error) {
+	req := httptest.NewRequest(http.MethodGet, p.URL, nil)
+	if p.Fragment {
+		req.Header.Set("HX-Request", "true")
+	}
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, req)
+
+	want := p.Status
+	if want == 0 {
+		want = http.StatusOK
+	}
+	if rec.Code != want {
+		return nil, false, fmt.Errorf("%s: got status %d, want %d", p.URL, rec.Code, want)
+	}
+	ct := rec.Header().Get("Content-Type")
+	if ct == "" { // as net/http would sniff it
+		ct = http.DetectContentType(rec.Body.Bytes())
+	}
+	isHTML = strings.HasPrefix(ct, "text/html")
+	return rec.Body.Bytes(), isHTML, nil
+}
+
+// rewriter replaces quoted attribute values equal to a key of links, in the
+// escaped form templ writes them, with the matching value.
+func rewriter(links map[string]string) *strings.Replacer {
+	var pairs []string
+	for _, from := range slices.Sorted(maps.Keys(links)) {
+		to := html.EscapeString(links[from])
+		pairs = append(pairs, `"`+html.EscapeString(from)+`"`, `"`+to+`"`)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+func writeFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
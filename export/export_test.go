@@ -0,0 +1,91 @@
+package export
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"portfolio/assets"
+)
+
+// testSite serves a page linking to the soccer tool and a fragment, a
+// fragment that htmx requests, and a plain-text file with the same link.
+func testSite(t *testing.T) Site {
+	t.Helper()
+	static, err := assets.NewManifest(fstest.MapFS{"css/site.css": {Data: []byte("body{}")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `<!DOCTYPE html><a href="/soccer">Soccer</a><a hx-get="/skills/detail?id=1&amp;x=2">Go</a>`)
+	})
+	mux.HandleFunc("GET /skills/detail", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("HX-Request") != "true" {
+			http.Error(w, "fragment only", http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, `<div>detail</div>`)
+	})
+	mux.HandleFunc("GET /robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, `"/soccer"`)
+	})
+	return Site{
+		Handler: mux,
+		Static:  static,
+		Pages: []Page{
+			{URL: "/", File: "index.html"},
+			{URL: "/skills/detail?id=1&x=2", File: "skills/detail/1.html", Fragment: true},
+			{URL: "/robots.txt", File: "robots.txt"},
+		},
+		Links:     map[string]string{"/soccer": "https://example.com/soccer"},
+		Redirects: []Redirect{{From: "/soccer", To: "https://example.com/soccer", Status: 302}},
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	if err := testSite(t).Write(dir, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<!DOCTYPE html><a href="https://example.com/soccer">Soccer</a><a hx-get="/skills/detail/1.html">Go</a>`
+	if got := readFile(t, filepath.Join(dir, "index.html")); got != want {
+		t.Errorf("index.html:\n got %s\nwant %s", got, want)
+	}
+	if got := readFile(t, filepath.Join(dir, "skills/detail/1.html")); got != `<div>detail</div>` {
+		t.Errorf("fragment = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "robots.txt")); got != `"/soccer"` {
+		t.Errorf("non-HTML file rewritten: %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, RedirectsFile)); got != "/soccer https://example.com/soccer 302\n" {
+		t.Errorf("%s = %q", RedirectsFile, got)
+	}
+	if got := readFile(t, filepath.Join(dir, "static/css/site.css")); got != "body{}" {
+		t.Errorf("static file = %q", got)
+	}
+}
+
+func TestWriteStatusMismatch(t *testing.T) {
+	site := testSite(t)
+	site.Pages = []Page{{URL: "/missing", File: "missing.html"}}
+	err := site.Write(t.TempDir(), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "got status 404, want 200") {
+		t.Errorf("got %v, want a status error", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package health serves the probes used by load balancers and uptime
+// monitors: liveness, readiness aggregated from checks that subsystems
+// register, and the build information of the running binary.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"portfolio/logging"
+)
+
+// DefaultTimeout bounds each readiness check when Registry.Timeout is zero.
+const DefaultTimeout = 2 * time.Second
+
+// Check reports whether one dependency is usable. It should return promptly
+// once ctx is done.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Registry holds readiness checks. The zero value is ready to use.
+type Registry struct {
+	// Timeout bounds each check; zero means DefaultTimeout.
+	Timeout time.Duration
+
+	mu     sync.Mutex
+	checks []namedCheck
+}
+
+// Register adds a check that must pass for the server to be ready.
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the readiness response body.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Statuses used in Report and Result.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check runs every registered check concurrently, each under its own
+// timeout, and reports ok only if all of them pass.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.Lock()
+	checks := r.checks
+	r.mu.Unlock()
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			start := time.Now()
+			err := c.check(ctx)
+			results[i] = Result{Status: StatusOK, Duration: time.Since(start).Round(time.Microsecond).String()}
+			if err != nil {
+				results[i].Status = StatusFail
+				results[i].Error = err.Error()
+			}
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+			logging.FromContext(ctx).Warn("health: check failed", "check", c.name, "err", results[i].Error)
+		}
+	}
+	return report
+}
+
+// Live answers 200 whenever the process can serve requests at all.
+func Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// Ready answers 200 with the check report when every check passes and 503
+// otherwise.
+func (r *Registry) Ready(w http.ResponseWriter, req *http.Request) {
+	report := r.Check(req.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
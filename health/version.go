@@ -0,0 +1,58 @@
+package health
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// Build stamps set with -ldflags "-X portfolio/health.revision=... -X
+// portfolio/health.buildTime=...", for builds where the go command cannot
+// record VCS information, such as the Docker image.
+var (
+	revision  string
+	buildTime string
+)
+
+// BuildInfo describes the running binary.
+type BuildInfo struct {
+	Module     string `json:"module"`
+	Version    string `json:"version"`
+	Revision   string `json:"revision,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"` // commit time of Revision
+	BuildTime  string `json:"build_time,omitempty"`
+	Modified   bool   `json:"modified"` // built from a dirty work tree
+	GoVersion  string `json:"go_version"`
+}
+
+// ReadBuildInfo returns the module version and the VCS stamp recorded by the
+// go command. Binaries built outside a repository, or with -buildvcs=false,
+// have no commit time and report the revision only if it was set with
+// -ldflags. The build time is only known when set with -ldflags.
+func ReadBuildInfo() BuildInfo {
+	b := BuildInfo{Version: "unknown"}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		b.Module = info.Main.Path
+		b.Version = info.Main.Version
+		b.GoVersion = info.GoVersion
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				b.Revision = s.Value
+			case "vcs.time":
+				b.CommitTime = s.Value
+			case "vcs.modified":
+				b.Modified = s.Value == "true"
+			}
+		}
+	}
+	if b.Revision == "" {
+		b.Revision = revision
+	}
+	b.BuildTime = buildTime
+	return b
+}
+
+// Version answers with the BuildInfo of the running binary.
+func Version(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, ReadBuildInfo())
+}
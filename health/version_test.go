@@ -0,0 +1,30 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersionStamps(t *testing.T) {
+	defer func(r, b string) { revision, buildTime = r, b }(revision, buildTime)
+	revision, buildTime = "0123abcd", "2026-01-02T03:04:05Z"
+
+	rec := httptest.NewRecorder()
+	Version(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	// Test binaries carry no VCS stamp, so the -ldflags values are used.
+	if got["revision"] != revision {
+		t.Errorf("revision = %v, want %q", got["revision"], revision)
+	}
+	if got["build_time"] != buildTime {
+		t.Errorf("build_time = %v, want %q", got["build_time"], buildTime)
+	}
+	if _, ok := got["time"]; ok {
+		t.Error("commit time reported as time")
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"portfolio/assets"
+	"portfolio/content"
+	"portfolio/health"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// testManifest fingerprints the embedded static files and loads the
+// embedded content, as the server does at startup.
+func testManifest(t *testing.T) *assets.Manifest {
+	t.Helper()
+	static, err := staticAssets("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := assets.NewManifest(static)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if siteContent, err = content.NewProvider("", static); err != nil {
+		t.Fatal(err)
+	}
+	return manifest
+}
+
+// testHandler returns the full middleware chain over the embedded assets.
+func testHandler(t *testing.T) http.Handler {
+	t.Helper()
+	return newHandler(discardLogger, testManifest(t), new(health.Registry))
+}
+
+func TestRoutingBeforeCSRF(t *testing.T) {
+	h := testHandler(t)
+	tests := []struct {
+		method, path string
+		want         int
+		allow        string
+		contentType  string
+	}{
+		{http.MethodPost, "/about", http.StatusMethodNotAllowed, "GET, HEAD", "text/html"},
+		{http.MethodDelete, "/api/v1/soccer/games", http.StatusMethodNotAllowed, "GET, HEAD", "application/json"},
+		{http.MethodPost, "/no-such-page", http.StatusNotFound, "", "text/html"},
+		{http.MethodPost, "/soccer/fetch", http.StatusForbidden, "", "text/html"},
+		{http.MethodPost, "/soccer/subscribe", http.StatusForbidden, "", "text/html"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader("")))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: got %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+		}
+		if got := rec.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: Allow %q, want %q", tt.method, tt.path, got, tt.allow)
+		}
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+			t.Errorf("%s %s: Content-Type %q, want %s", tt.method, tt.path, got, tt.contentType)
+		}
+	}
+}
+
+// Misses under /static/ render the site's 404 page, or an error toast for
+// htmx, like any other unknown path.
+func TestStaticNotFound(t *testing.T) {
+	h := testHandler(t)
+	tests := []struct {
+		path string
+		htmx bool
+	}{
+		{"/static/missing.css", false},
+		{"/static/css/", false},
+		{"/static/css/styles.css.gz", false},
+		{"/static/css/styles.0000000000.css", false},
+		{"/static/missing.js", true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: got %d, want 404", tt.path, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/html") {
+			t.Errorf("%s: Content-Type %q, want text/html", tt.path, got)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+			t.Errorf("%s: Cache-Control %q, want no-store", tt.path, got)
+		}
+		if got := rec.Header().Get("HX-Retarget") != ""; got != tt.htmx {
+			t.Errorf("%s: retargeted to the toast region = %v, want %v", tt.path, got, tt.htmx)
+		}
+	}
+}
+
+// Fragment endpoints return bare HTML only to htmx. Direct navigation and
+// htmx history restores, which replace the whole page, get the full page.
+func TestFragmentsServeFullPagesOnNavigation(t *testing.T) {
+	h := testHandler(t)
+	tests := []struct {
+		path     string
+		fragment string // class only the fragment's markup has
+	}{
+		{"/skills/grid", "skills-grid"},
+		{"/projects/grid", "projects-grid"},
+		{"/skills/detail?id=5", "skill-detail-card"},
+	}
+	headers := []struct {
+		name         string
+		header       map[string]string
+		wantFragment bool
+	}{
+		{"direct", nil, false},
+		{"htmx", map[string]string{"HX-Request": "true"}, true},
+		{"history restore", map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"}, false},
+	}
+	for _, tt := range tests {
+		for _, hd := range headers {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range hd.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			body := rec.Body.String()
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("%s %s: got %d", hd.name, tt.path, rec.Code)
+				continue
+			}
+			if got := rec.Header().Values("Vary"); !slices.Contains(got, "HX-Request") {
+				t.Errorf("%s %s: Vary %q lacks HX-Request", hd.name, tt.path, got)
+			}
+			if !strings.Contains(body, tt.fragment) {
+				t.Errorf("%s %s: body lacks the %s fragment", hd.name, tt.path, tt.fragment)
+			}
+			if page := strings.Contains(body, "<html"); page == hd.wantFragment {
+				t.Errorf("%s %s: full page = %v, want %v", hd.name, tt.path, page, !hd.wantFragment)
+			}
+		}
+	}
+}
+
+func TestPagesCarryCSRFToken(t *testing.T) {
+	rec := httptest.NewRecorder()
+	testHandler(t).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rec.Body.String(), "hx-headers=") {
+		t.Error("page has no hx-headers CSRF token")
+	}
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		origin     string
+		soccerLink string // expected in index.html; "" for none at all
+	}{
+		{"", ""},
+		{"https://example.com", `href="https://example.com/soccer"`},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if err := newExportSite(discardLogger, testManifest(t), tt.origin).Write(dir, io.Discard); err != nil {
+			t.Fatalf("origin %q: %v", tt.origin, err)
+		}
+
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || filepath.Ext(path) != ".html" {
+				return err
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			page, _ := filepath.Rel(dir, path)
+			if strings.Contains(string(b), `href="/soccer`) {
+				t.Errorf("origin %q: %s links to /soccer, which the export does not serve", tt.origin, page)
+			}
+			if strings.Contains(string(b), "hx-headers") || strings.Contains(string(b), "X-CSRF-Token") {
+				t.Errorf("origin %q: %s carries a CSRF token", tt.origin, page)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.soccerLink == "" && strings.Contains(string(index), "Soccer") {
+			t.Errorf("origin %q: index.html still mentions the soccer tool", tt.origin)
+		}
+		if tt.soccerLink != "" && !strings.Contains(string(index), tt.soccerLink) {
+			t.Errorf("origin %q: index.html has no %s", tt.origin, tt.soccerLink)
+		}
+	}
+}
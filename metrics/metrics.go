@@ -0,0 +1,143 @@
+// Package metrics exposes server and soccer tool metrics in the Prometheus
+// text format. Collectors are package-level and registered on Registry,
+// which Handler serves.
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every collector served by Handler, along with the Go
+// runtime and process collectors.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+/*
+========================================
+HTTP
+========================================
+*/
+
+// UnmatchedRoute labels requests that matched no route, so probes for
+// arbitrary paths share one series.
+const UnmatchedRoute = "unmatched"
+
+// OtherMethod labels requests with a method outside the standard set, so
+// clients cannot create a series per made-up method.
+const OtherMethod = "other"
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route pattern.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// ObserveRequest records one served request. route is the matched mux
+// pattern, or UnmatchedRoute.
+func ObserveRequest(method, route string, status int, d time.Duration) {
+	method = methodLabel(method)
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(d.Seconds())
+}
+
+// methodLabel returns method if it is one the site can route, and
+// OtherMethod otherwise. Methods are case-sensitive, so "get" is other.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return OtherMethod
+}
+
+/*
+========================================
+Soccer
+========================================
+*/
+
+var (
+	// SoccerFetches counts schedule fetches from the provider, per team
+	// code list, from the tool, the API and the watcher.
+	SoccerFetches = factory.NewCounter(prometheus.CounterOpts{
+		Name: "soccer_fetches_total",
+		Help: "Schedule fetches from the soccer provider.",
+	})
+
+	// SoccerProviderErrors counts fetches the provider failed.
+	SoccerProviderErrors = factory.NewCounter(prometheus.CounterOpts{
+		Name: "soccer_provider_errors_total",
+		Help: "Schedule fetches that failed at the soccer provider.",
+	})
+
+	// SoccerCacheHits counts API requests answered 304 Not Modified
+	// because the client's cached copy was still current.
+	SoccerCacheHits = factory.NewCounter(prometheus.CounterOpts{
+		Name: "soccer_cache_hits_total",
+		Help: "Soccer API requests answered from the client's cache with 304 Not Modified.",
+	})
+
+	// SoccerICSDownloads counts calendar files served.
+	SoccerICSDownloads = factory.NewCounter(prometheus.CounterOpts{
+		Name: "soccer_ics_downloads_total",
+		Help: "ICS calendar files downloaded.",
+	})
+)
+
+// WatchSubscriptions exports the number of active schedule-change
+// subscriptions, read from count at scrape time.
+func WatchSubscriptions(count func() int) {
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "soccer_active_subscriptions",
+		Help: "Active schedule-change subscriptions.",
+	}, func() float64 { return float64(count()) })
+}
+
+/*
+========================================
+Handler
+========================================
+*/
+
+// Handler serves Registry. A non-empty token must be presented as
+// "Authorization: Bearer <token>".
+func Handler(token string) http.Handler {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
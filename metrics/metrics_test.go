@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveRequestMethodLabel(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"GET", "GET"},
+		{"HEAD", "HEAD"},
+		{"POST", "POST"},
+		{"PUT", "PUT"},
+		{"PATCH", "PATCH"},
+		{"DELETE", "DELETE"},
+		{"OPTIONS", "OPTIONS"},
+		{"TRACE", OtherMethod},
+		{"CONNECT", OtherMethod},
+		{"get", OtherMethod},
+		{"XYZZY", OtherMethod},
+	}
+	for _, tt := range tests {
+		before := testutil.ToFloat64(httpRequests.WithLabelValues(tt.want, UnmatchedRoute, "404"))
+		ObserveRequest(tt.method, UnmatchedRoute, 404, time.Millisecond)
+		after := testutil.ToFloat64(httpRequests.WithLabelValues(tt.want, UnmatchedRoute, "404"))
+		if after != before+1 {
+			t.Errorf("%s: not counted under method %q", tt.method, tt.want)
+		}
+	}
+
+	// Only the standard methods and other have series.
+	if n := testutil.CollectAndCount(httpRequests); n != 8 {
+		t.Errorf("%d request series, want 8", n)
+	}
+}
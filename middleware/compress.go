@@ -0,0 +1,293 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content codings produced by Compress, in order of preference.
+const (
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+)
+
+// CompressOptions configures Compress. Zero fields take the defaults.
+type CompressOptions struct {
+	// MinSize is the smallest body, in bytes, worth compressing.
+	MinSize int
+	// Types lists the compressible media types, without parameters.
+	Types []string
+}
+
+// DefaultCompressOptions are used for zero fields of CompressOptions.
+var DefaultCompressOptions = CompressOptions{
+	MinSize: 1024,
+	Types: []string{
+		"text/html",
+		"text/css",
+		"text/plain",
+		"text/javascript",
+		"text/calendar",
+		"application/javascript",
+		"application/json",
+		"application/manifest+json",
+		"application/xml",
+		"image/svg+xml",
+	},
+}
+
+var (
+	gzipWriters   = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	brotliWriters = sync.Pool{New: func() any { return brotli.NewWriterLevel(io.Discard, 5) }}
+)
+
+// Compress encodes responses with Brotli or gzip when the client accepts it,
+// the body is at least MinSize bytes and its Content-Type is in Types.
+// Responses that already carry a Content-Encoding, such as precompressed
+// static files, and range responses pass through untouched.
+func Compress(opts CompressOptions) Middleware {
+	if opts.MinSize <= 0 {
+		opts.MinSize = DefaultCompressOptions.MinSize
+	}
+	if len(opts.Types) == 0 {
+		opts.Types = DefaultCompressOptions.Types
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := PreferredEncoding(r, EncodingBrotli, EncodingGzip)
+			if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressWriter{ResponseWriter: w, opts: &opts, encoding: encoding}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// PreferredEncoding returns the first of offered that r's Accept-Encoding
+// header allows, or "" if none is acceptable. Codings with q=0 are refused.
+func PreferredEncoding(r *http.Request, offered ...string) string {
+	accepted := map[string]bool{}
+	wildcard := false
+	for part := range strings.SplitSeq(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		ok := true
+		if q, found := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); found {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				ok = false
+			}
+		}
+		if coding == "*" {
+			wildcard = ok
+			continue
+		}
+		accepted[coding] = ok
+	}
+	for _, enc := range offered {
+		if ok, listed := accepted[enc]; ok || (!listed && wildcard) {
+			return enc
+		}
+	}
+	return ""
+}
+
+// compressWriter buffers the start of the body until it knows whether the
+// response is worth compressing, then either starts an encoder or writes
+// the buffered bytes through unchanged.
+type compressWriter struct {
+	http.ResponseWriter
+	opts     *CompressOptions
+	encoding string
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status != 0 || cw.decided {
+		return
+	}
+	cw.status = status
+	// Informational and bodiless responses are never compressed.
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if cw.decided {
+		if cw.enc != nil {
+			return cw.enc.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+	if cw.buf == nil && !cw.compressible() {
+		cw.decide(false)
+		return cw.ResponseWriter.Write(b)
+	}
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.opts.MinSize {
+		if err := cw.flushBuffer(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// compressible reports whether the headers set so far allow compression.
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	ct := h.Get("Content-Type")
+	if ct == "" {
+		return true // decided by sniffing once the buffer is flushed
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	return err == nil && slices.Contains(cw.opts.Types, mediaType)
+}
+
+// flushBuffer commits the headers and writes the buffered bytes, through an
+// encoder when compress is true.
+func (cw *compressWriter) flushBuffer(compress bool) error {
+	if cw.Header().Get("Content-Type") == "" && len(cw.buf) > 0 {
+		cw.Header().Set("Content-Type", http.DetectContentType(cw.buf))
+		compress = compress && cw.compressible()
+	}
+	cw.decide(compress)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// decide commits the headers, starting an encoder when compress is true.
+func (cw *compressWriter) decide(compress bool) {
+	if cw.decided {
+		return
+	}
+	cw.decided = true
+	h := cw.Header()
+	if compress {
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		// The encoded body differs from the identity one, so a strong
+		// validator would be wrong.
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		switch cw.encoding {
+		case EncodingBrotli:
+			bw := brotliWriters.Get().(*brotli.Writer)
+			bw.Reset(cw.ResponseWriter)
+			cw.enc = bw
+		case EncodingGzip:
+			gw := gzipWriters.Get().(*gzip.Writer)
+			gw.Reset(cw.ResponseWriter)
+			cw.enc = gw
+		}
+	}
+	if compress || cw.compressibleType() {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	status := cw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+// compressibleType reports whether the response's media type is one that
+// would be compressed for a large enough body, so caches must key on
+// Accept-Encoding.
+func (cw *compressWriter) compressibleType() bool {
+	mediaType, _, err := mime.ParseMediaType(cw.Header().Get("Content-Type"))
+	return err == nil && slices.Contains(cw.opts.Types, mediaType) && cw.Header().Get("Content-Encoding") == ""
+}
+
+// Close writes any buffered bytes uncompressed, since the body turned out
+// smaller than MinSize, or finishes the encoder.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			return nil // handler wrote nothing; let net/http send its default
+		}
+		if err := cw.flushBuffer(false); err != nil {
+			return err
+		}
+	}
+	if cw.enc == nil {
+		return nil
+	}
+	err := cw.enc.Close()
+	switch enc := cw.enc.(type) {
+	case *brotli.Writer:
+		enc.Reset(io.Discard)
+		brotliWriters.Put(enc)
+	case *gzip.Writer:
+		enc.Reset(io.Discard)
+		gzipWriters.Put(enc)
+	}
+	cw.enc = nil
+	return err
+}
+
+// Flush sends buffered data to the client. A flush before MinSize bytes are
+// buffered commits to compression, since streaming responses are usually
+// long.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if err := cw.flushBuffer(cw.compressible()); err != nil {
+			return
+		}
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Hijack implements http.Hijacker when the underlying writer supports it.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("middleware: response writer does not support hijacking")
+	}
+	return h.Hijack()
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestPreferredEncoding(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", EncodingGzip},
+		{"gzip, br", EncodingBrotli},
+		{"GZIP", EncodingGzip},
+		{"br;q=0, gzip", EncodingGzip},
+		{"br; q=0, gzip;q=0", ""},
+		{"br;q=0.5", EncodingBrotli},
+		{"*", EncodingBrotli},
+		{"*;q=0", ""},
+		{"br;q=0, *", EncodingGzip},
+		{"deflate, compress", ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", tt.accept)
+		if got := PreferredEncoding(r, EncodingBrotli, EncodingGzip); got != tt.want {
+			t.Errorf("Accept-Encoding %q: got %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	page := "<!DOCTYPE html><html><body>" + strings.Repeat("<p>schedule</p>", 200) + "</body></html>"
+	html := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = io.WriteString(w, page)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		accept   string
+		header   http.Header
+		handler  http.HandlerFunc
+		encoding string
+		vary     bool
+		etag     string
+	}{
+		{name: "brotli", accept: "gzip, br", handler: html, encoding: EncodingBrotli, vary: true, etag: `W/"abc"`},
+		{name: "gzip", accept: "gzip", handler: html, encoding: EncodingGzip, vary: true, etag: `W/"abc"`},
+		{name: "not accepted", accept: "identity", handler: html, etag: `"abc"`},
+		{name: "refused with q=0", accept: "br;q=0, gzip;q=0", handler: html, etag: `"abc"`},
+		{name: "head", method: http.MethodHead, accept: "br", handler: html, etag: `"abc"`},
+		{name: "range", accept: "br", header: http.Header{"Range": {"bytes=0-10"}}, handler: html, etag: `"abc"`},
+		{name: "below min size", accept: "br", handler: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "<p>short</p>")
+		}, vary: true},
+		{name: "incompressible type", accept: "br", handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = io.WriteString(w, page)
+		}},
+		{name: "already encoded", accept: "br", handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/css")
+			w.Header().Set("Content-Encoding", EncodingGzip)
+			_, _ = io.WriteString(w, page)
+		}, encoding: EncodingGzip},
+		{name: "not modified", accept: "br", handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotModified)
+		}, vary: true},
+		{name: "sniffed json", accept: "gzip", handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = io.WriteString(w, `{"games":"`+strings.Repeat("x", 2000)+`"}`)
+		}, encoding: EncodingGzip, vary: true},
+	}
+	for _, tt := range tests {
+		method := tt.method
+		if method == "" {
+			method = http.MethodGet
+		}
+		r := httptest.NewRequest(method, "/", nil)
+		r.Header.Set("Accept-Encoding", tt.accept)
+		for k, v := range tt.header {
+			r.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		Compress(CompressOptions{})(tt.handler).ServeHTTP(rec, r)
+
+		if got := rec.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("%s: Content-Encoding %q, want %q", tt.name, got, tt.encoding)
+		}
+		if got := rec.Header().Get("Vary") == "Accept-Encoding"; got != tt.vary {
+			t.Errorf("%s: Vary %q, want Accept-Encoding %t", tt.name, rec.Header().Get("Vary"), tt.vary)
+		}
+		if got := rec.Header().Get("ETag"); got != tt.etag {
+			t.Errorf("%s: ETag %q, want %q", tt.name, got, tt.etag)
+		}
+		if tt.encoding != "" && rec.Header().Get("Content-Length") != "" {
+			t.Errorf("%s: Content-Length kept on an encoded body", tt.name)
+		}
+	}
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	body := strings.Repeat("BEGIN:VEVENT\r\nSUMMARY:Game\r\nEND:VEVENT\r\n", 100)
+	h := Compress(CompressOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		// Write in small pieces so the buffer crosses MinSize mid-write.
+		for chunk := range strings.SplitAfterSeq(body, "\r\n") {
+			_, _ = io.WriteString(w, chunk)
+		}
+	}))
+
+	for _, encoding := range []string{EncodingBrotli, EncodingGzip} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", encoding)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+
+		var dec io.Reader
+		switch encoding {
+		case EncodingBrotli:
+			dec = brotli.NewReader(rec.Body)
+		case EncodingGzip:
+			gr, err := gzip.NewReader(rec.Body)
+			if err != nil {
+				t.Fatalf("%s: %v", encoding, err)
+			}
+			dec = gr
+		}
+		got, err := io.ReadAll(dec)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+		if string(got) != body {
+			t.Errorf("%s: decoded body differs from the original", encoding)
+		}
+		if rec.Body.Len() >= len(body) {
+			t.Errorf("%s: %d encoded bytes for a %d byte body", encoding, rec.Body.Len(), len(body))
+		}
+	}
+}
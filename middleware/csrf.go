@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"portfolio/reqctx"
+)
+
+// CSRF names used by forms and HTMX requests.
+const (
+	CSRFCookie    = "csrf_token"
+	CSRFHeader    = "X-CSRF-Token"
+	CSRFFormField = "csrf_token"
+)
+
+// CSRFToken gives each browser a random double-submit token in CSRFCookie,
+// set on the first uncached HTML page it loads, and exposes it to
+// components via reqctx.CSRFToken. It does not check anything itself;
+// routes that accept unsafe methods are wrapped in RequireCSRF. CSRFToken
+// must run after Values.
+func CSRFToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := cookieCSRFToken(r); token != "" {
+			reqctx.From(r.Context()).CSRFToken = token
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := newCSRFToken()
+		reqctx.From(r.Context()).CSRFToken = token
+		cw := &csrfCookieWriter{ResponseWriter: w, cookie: &http.Cookie{
+			Name:     CSRFCookie,
+			Value:    token,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+			SameSite: http.SameSiteLaxMode,
+		}}
+		next.ServeHTTP(cw, r)
+		cw.commit(nil)
+	})
+}
+
+// RequireCSRF rejects unsafe requests that do not echo the CSRFCookie token
+// in CSRFHeader or the CSRFFormField form value. Rejected requests are
+// answered by failed with a 403 status. It wraps individual routes, so
+// unknown paths and methods still get their 404 and 405 responses.
+func RequireCSRF(failed http.Handler) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !safeMethod(r.Method) {
+				token := cookieCSRFToken(r)
+				sent := r.Header.Get(CSRFHeader)
+				if sent == "" {
+					sent = r.PostFormValue(CSRFFormField)
+				}
+				if token == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+					failed.ServeHTTP(w, r)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// cookieCSRFToken returns the request's CSRFCookie token, or "" if it is
+// missing or malformed.
+func cookieCSRFToken(r *http.Request) string {
+	if c, err := r.Cookie(CSRFCookie); err == nil && validCSRFToken(c.Value) {
+		return c.Value
+	}
+	return ""
+}
+
+// csrfCookieWriter sets a new token's cookie on the response, but only if
+// the response is an HTML page that may not be stored by shared caches.
+// Pages are what carry the token to forms; a cookie on a cacheable static
+// file or API response could be stored and replayed to every visitor.
+type csrfCookieWriter struct {
+	http.ResponseWriter
+	cookie    *http.Cookie
+	status    int  // held until the content type is known
+	committed bool // headers decided and, if status is set, written
+}
+
+func (cw *csrfCookieWriter) WriteHeader(status int) {
+	if cw.committed {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	// templ renders leave the content type to be sniffed from the body.
+	cw.status = status
+	if cw.Header().Get("Content-Type") != "" || status < 200 || status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.commit(nil)
+	}
+}
+
+func (cw *csrfCookieWriter) Write(b []byte) (int, error) {
+	cw.commit(b)
+	return cw.ResponseWriter.Write(b)
+}
+
+// commit decides whether to set the cookie, sniffing the content type from
+// the first body bytes when the handler did not set one, and writes any
+// held status.
+func (cw *csrfCookieWriter) commit(body []byte) {
+	if cw.committed {
+		return
+	}
+	cw.committed = true
+	h := cw.Header()
+	contentType := h.Get("Content-Type")
+	if contentType == "" && len(body) > 0 {
+		contentType = http.DetectContentType(body)
+		h.Set("Content-Type", contentType)
+	}
+	if strings.HasPrefix(contentType, "text/html") && !sharedCacheable(h.Get("Cache-Control")) {
+		http.SetCookie(cw.ResponseWriter, cw.cookie)
+	}
+	if cw.status != 0 {
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *csrfCookieWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Flush implements http.Flusher for streaming handlers.
+func (cw *csrfCookieWriter) Flush() {
+	cw.commit(nil)
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// sharedCacheable reports whether a Cache-Control value lets shared caches
+// store the response.
+func sharedCacheable(cacheControl string) bool {
+	cacheable := false
+	for directive := range strings.SplitSeq(strings.ToLower(cacheControl), ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch name {
+		case "no-store", "private":
+			return false
+		case "public", "s-maxage", "max-age", "immutable":
+			cacheable = true
+		}
+	}
+	return cacheable
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func newCSRFToken() string {
+	var b [32]byte
+	_, _ = rand.Read(b[:])
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// validCSRFToken rejects cookies that could not have been issued by
+// newCSRFToken.
+func validCSRFToken(token string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(b) == 32
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"portfolio/reqctx"
+)
+
+// withValues runs h with request-scoped values, as Values would.
+func withValues(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(reqctx.NewContext(r.Context(), &reqctx.Values{})))
+	})
+}
+
+func TestCSRFCookieOnlyOnUncachedHTML(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantCookie bool
+	}{
+		{"templ page", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("<!DOCTYPE html><html><body>page</body></html>"))
+		}, true},
+		{"html error page", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<p>not found</p>"))
+		}, true},
+		{"status before sniffed body", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("<div>fragment</div>"))
+		}, true},
+		{"immutable static file", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			w.Header().Set("Content-Type", "text/css; charset=utf-8")
+			_, _ = w.Write([]byte("body{}"))
+		}, false},
+		{"cacheable html", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "public, max-age=60")
+			_, _ = w.Write([]byte("<html></html>"))
+		}, false},
+		{"api json", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "public, max-age=300")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"games":[]}`))
+		}, false},
+		{"probe", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		}, false},
+		{"metrics", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("# HELP up\nup 1\n"))
+		}, false},
+		{"empty response", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}, false},
+	}
+	for _, tt := range tests {
+		var token string
+		h := withValues(CSRFToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token = reqctx.CSRFToken(r.Context())
+			tt.handler(w, r)
+		})))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		setCookie := rec.Header().Get("Set-Cookie")
+		if got := strings.HasPrefix(setCookie, CSRFCookie+"="+token+";"); got != tt.wantCookie {
+			t.Errorf("%s: Set-Cookie %q, want cookie %t", tt.name, setCookie, tt.wantCookie)
+		}
+		if token == "" {
+			t.Errorf("%s: no token in the request context", tt.name)
+		}
+	}
+}
+
+func TestCSRFKeepsExistingToken(t *testing.T) {
+	token := newCSRFToken()
+	var seen string
+	h := withValues(CSRFToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = reqctx.CSRFToken(r.Context())
+		_, _ = w.Write([]byte("<html></html>"))
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: token})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if seen != token {
+		t.Errorf("token = %q, want the cookie's %q", seen, token)
+	}
+	if c := rec.Header().Get("Set-Cookie"); c != "" {
+		t.Errorf("Set-Cookie %q for a visitor who has a token", c)
+	}
+}
+
+func TestRequireCSRF(t *testing.T) {
+	token := newCSRFToken()
+	tests := []struct {
+		name   string
+		method string
+		cookie string
+		header string
+		form   string
+		want   int
+	}{
+		{"safe method needs no token", http.MethodGet, "", "", "", http.StatusOK},
+		{"header token", http.MethodPost, token, token, "", http.StatusOK},
+		{"form token", http.MethodPost, token, "", token, http.StatusOK},
+		{"missing token", http.MethodPost, token, "", "", http.StatusForbidden},
+		{"mismatched token", http.MethodPost, token, newCSRFToken(), "", http.StatusForbidden},
+		{"no cookie", http.MethodPost, "", token, "", http.StatusForbidden},
+		{"malformed cookie", http.MethodPost, "short", "short", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		h := RequireCSRF(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		var body *strings.Reader
+		if tt.form != "" {
+			body = strings.NewReader(url.Values{CSRFFormField: {tt.form}}.Encode())
+		} else {
+			body = strings.NewReader("")
+		}
+		req := httptest.NewRequest(tt.method, "/soccer/fetch", body)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+		}
+		if tt.header != "" {
+			req.Header.Set(CSRFHeader, tt.header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"portfolio/logging"
+)
+
+type routeKey struct{}
+
+// Logger stores a request-scoped logger in the request context. Every record
+// it writes carries the request ID, user agent, trace ID when the request is
+// traced and, once SetRoute has been called by the router, the matched route
+// pattern. Logger must run after RequestID and Trace.
+func Logger(base *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := new(atomic.Pointer[string])
+			logger := slog.New(routeHandler{Handler: base.Handler(), route: route}).With(
+				slog.String("request_id", RequestIDFrom(r.Context())),
+				slog.String("user_agent", r.UserAgent()),
+			)
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsSampled() {
+				logger = logger.With(slog.String("trace_id", sc.TraceID().String()))
+			}
+			ctx := context.WithValue(r.Context(), routeKey{}, route)
+			ctx = logging.NewContext(ctx, logger)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// SetRoute records the route pattern matched for the request so that it is
+// included in every log record written through the request's logger,
+// including the access log line written after the handler returns, and
+// names the request's trace span after it.
+func SetRoute(ctx context.Context, pattern string) {
+	if route, ok := ctx.Value(routeKey{}).(*atomic.Pointer[string]); ok {
+		route.Store(&pattern)
+	}
+	span := trace.SpanFromContext(ctx)
+	span.SetName(pattern)
+	span.SetAttributes(attribute.String("http.route", pattern))
+}
+
+// Route returns the route pattern recorded by SetRoute, or "" if the request
+// matched no route.
+func Route(ctx context.Context) string {
+	if route, ok := ctx.Value(routeKey{}).(*atomic.Pointer[string]); ok {
+		if pattern := route.Load(); pattern != nil {
+			return *pattern
+		}
+	}
+	return ""
+}
+
+// routeHandler adds the route pattern to records. The pattern is only known
+// after routing, so it is read when each record is handled rather than
+// bound with Logger.With up front.
+type routeHandler struct {
+	slog.Handler
+	route *atomic.Pointer[string]
+}
+
+func (h routeHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if pattern := h.route.Load(); pattern != nil {
+		rec = rec.Clone()
+		rec.AddAttrs(slog.String("route", *pattern))
+	}
+	return h.Handler.Handle(ctx, rec)
+}
+
+func (h routeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return routeHandler{Handler: h.Handler.WithAttrs(attrs), route: h.route}
+}
+
+func (h routeHandler) WithGroup(name string) slog.Handler {
+	return routeHandler{Handler: h.Handler.WithGroup(name), route: h.route}
+}
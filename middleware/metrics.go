@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"portfolio/metrics"
+)
+
+// Metrics records the count and latency of every request, labelled with
+// the route pattern passed to SetRoute rather than the raw path, so the
+// number of series stays bounded. Metrics must run after Logger.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := wrap(w)
+		next.ServeHTTP(rw, r)
+
+		route := Route(r.Context())
+		if route == "" {
+			route = metrics.UnmatchedRoute
+		}
+		metrics.ObserveRequest(r.Method, route, rw.Status(), time.Since(start))
+	})
+}
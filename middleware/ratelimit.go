@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter allows each key a fixed number of requests per window. Windows
+// are fixed rather than sliding, which is enough to stop a single client
+// from hammering an endpoint and needs one counter per active key.
+type RateLimiter struct {
+	requests int
+	window   time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter returns a limiter allowing requests per window for each key.
+func NewRateLimiter(requests int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		requests: requests,
+		window:   window,
+		now:      time.Now,
+		windows:  make(map[string]*rateWindow),
+	}
+}
+
+// Allow counts a request for key and reports whether it is within the
+// limit. When it is not, retryAfter is the time until the window resets.
+func (l *RateLimiter) Allow(key string) (ok bool, retryAfter time.Duration) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget expired windows once per window, so keys seen once do not
+	// accumulate.
+	if now.Sub(l.lastSweep) >= l.window {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, found := l.windows[key]
+	if !found || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+	if w.count >= l.requests {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// RateLimit limits requests per client IP with l. Requests over the limit
+// get a Retry-After header and are answered by exceeded, which should
+// respond 429.
+func RateLimit(l *RateLimiter, exceeded http.Handler) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ok, retryAfter := l.Allow(ClientIP(r)); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				exceeded.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the IP address of the connection r arrived on.
+// Forwarding headers are ignored because any client can set them; behind a
+// reverse proxy every request shares the proxy's address.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
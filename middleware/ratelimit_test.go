@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for i := range 2 {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d refused", i+1)
+		}
+	}
+	now = now.Add(20 * time.Second)
+	ok, retryAfter := l.Allow("a")
+	if ok || retryAfter != 40*time.Second {
+		t.Errorf("third request = %t, %s; want refused for 40s", ok, retryAfter)
+	}
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("another key shares the limit")
+	}
+
+	now = now.Add(40 * time.Second)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("request refused after the window reset")
+	}
+
+	now = now.Add(2 * time.Minute)
+	l.Allow("c")
+	if len(l.windows) != 1 {
+		t.Errorf("%d windows kept after the sweep, want 1", len(l.windows))
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limited := RateLimit(NewRateLimiter(1, time.Hour), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = remote
+		req.Header.Set("X-Forwarded-For", "203.0.113.9") // ignored
+		rec := httptest.NewRecorder()
+		limited.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := send("192.0.2.1:1000"); rec.Code != http.StatusOK {
+		t.Fatalf("first request: %d", rec.Code)
+	}
+	rec := send("192.0.2.1:2000")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("second request from the same IP: %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := send("192.0.2.2:1000"); rec.Code != http.StatusOK {
+		t.Errorf("request from another IP: %d", rec.Code)
+	}
+}
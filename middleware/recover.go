@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"portfolio/logging"
+)
+
+// Recover catches panics from downstream handlers, logs them with the stack
+// trace through the request's logger, and serves fallback as a 500 response.
+// If the handler had already started the response, the connection is
+// aborted instead since a clean error page can no longer be sent.
+func Recover(fallback http.Handler) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := wrap(w)
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(rec)
+				}
+				logging.FromContext(r.Context()).Error("panic",
+					"panic", rec, "method", r.Method, "path", r.URL.Path, "stack", string(debug.Stack()))
+				if rw.wroteHeader() {
+					panic(http.ErrAbortHandler)
+				}
+				rw.Header().Del("Content-Length")
+				rw.Header().Del("Content-Encoding")
+				fallback.ServeHTTP(rw, r)
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
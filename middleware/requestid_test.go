@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		incoming string
+		reused   bool
+	}{
+		{"", false},
+		{"abc-123_DEF.4", true},
+		{strings.Repeat("a", 64), true},
+		{strings.Repeat("a", 65), false},
+		{"has space", false},
+		{"new\nline", false},
+		{"quote\"", false},
+		{"ünïcode", false},
+	}
+	for _, tt := range tests {
+		var seen string
+		h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = RequestIDFrom(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.incoming != "" {
+			req.Header.Set(RequestIDHeader, tt.incoming)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if seen == "" || rec.Header().Get(RequestIDHeader) != seen {
+			t.Errorf("%q: context ID %q, response header %q", tt.incoming, seen, rec.Header().Get(RequestIDHeader))
+		}
+		if reused := seen == tt.incoming; reused != tt.reused {
+			t.Errorf("%q: reused = %t, want %t (got %q)", tt.incoming, reused, tt.reused, seen)
+		}
+		if !tt.reused && !validRequestID(seen) {
+			t.Errorf("%q: generated ID %q is not valid", tt.incoming, seen)
+		}
+	}
+}
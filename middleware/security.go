@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"portfolio/logging"
+	"portfolio/reqctx"
+)
+
+// CSPReportPath receives Content-Security-Policy violation reports. It is
+// posted to cross-site by browsers, so it must not require a CSRF token.
+const CSPReportPath = "/csp-report"
+
+// SecurityOptions configures SecurityHeaders.
+type SecurityOptions struct {
+	// HSTSMaxAge is sent in Strict-Transport-Security; zero omits the
+	// header. Browsers ignore it on plain HTTP responses.
+	HSTSMaxAge time.Duration
+	// CSP returns the Content-Security-Policy for a request given its
+	// nonce. Nil sends no policy.
+	CSP func(nonce string) string
+	// CSPReportOnly sends the policy as Content-Security-Policy-Report-Only,
+	// so violations are reported to CSPReportPath but not blocked.
+	CSPReportOnly bool
+}
+
+// SecurityHeaders sets the browser hardening headers on every response and
+// a Content-Security-Policy built with the request's nonce, which scripts in
+// components carry via reqctx.Nonce. SecurityHeaders must run after Values.
+func SecurityHeaders(opts SecurityOptions) Middleware {
+	cspHeader := "Content-Security-Policy"
+	if opts.CSPReportOnly {
+		cspHeader = "Content-Security-Policy-Report-Only"
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if opts.HSTSMaxAge > 0 {
+				h.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(int(opts.HSTSMaxAge.Seconds())))
+			}
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("X-Frame-Options", "DENY")
+			h.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			h.Set("Permissions-Policy", "camera=(), microphone=(), geolocation=(), payment=(), usb=(), browsing-topics=()")
+			if opts.CSP != nil {
+				h.Set("Reporting-Endpoints", `csp="`+CSPReportPath+`"`)
+				h.Set(cspHeader, opts.CSP(reqctx.Nonce(r.Context())))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CSP report limits. A single violation report is well under 1KB, so the
+// body limit leaves room for a small batch. Logging is capped per report
+// and across all clients so that a noisy page or a forged flood cannot
+// drown out the rest of the log.
+const (
+	maxCSPReportSize         = 8 << 10
+	maxCSPViolationsPerBatch = 5
+)
+
+// cspLogLimit bounds how many violations CSPReport logs per minute.
+var cspLogLimit = NewRateLimiter(100, time.Minute)
+
+// cspViolation holds the fields of a violation report worth logging. Legacy
+// report-uri reports use the hyphenated keys, Reporting API reports the
+// camel-cased ones.
+type cspViolation struct {
+	DocumentURI        string `json:"document-uri"`
+	ViolatedDirective  string `json:"violated-directive"`
+	EffectiveDirective string `json:"effective-directive"`
+	BlockedURI         string `json:"blocked-uri"`
+	SourceFile         string `json:"source-file"`
+	LineNumber         int    `json:"line-number"`
+	Disposition        string `json:"disposition"`
+
+	DocumentURL           string `json:"documentURL"`
+	EffectiveDirectiveAPI string `json:"effectiveDirective"`
+	BlockedURL            string `json:"blockedURL"`
+	SourceFileAPI         string `json:"sourceFile"`
+	LineNumberAPI         int    `json:"lineNumber"`
+}
+
+func (v cspViolation) attrs() []slog.Attr {
+	pick := func(a, b string) string {
+		if a != "" {
+			return a
+		}
+		return b
+	}
+	line := v.LineNumber
+	if line == 0 {
+		line = v.LineNumberAPI
+	}
+	directive := pick(v.EffectiveDirective, pick(v.EffectiveDirectiveAPI, v.ViolatedDirective))
+	return []slog.Attr{
+		slog.String("document", pick(v.DocumentURI, v.DocumentURL)),
+		slog.String("directive", directive),
+		slog.String("blocked", pick(v.BlockedURI, v.BlockedURL)),
+		slog.String("source", pick(v.SourceFile, v.SourceFileAPI)),
+		slog.Int("line", line),
+		slog.String("disposition", v.Disposition),
+	}
+}
+
+// CSPReport logs the Content-Security-Policy violations posted to
+// CSPReportPath, accepting both application/csp-report bodies and
+// application/reports+json batches, and answers 204. It logs at most
+// maxCSPViolationsPerBatch violations of a batch and drops the rest once
+// cspLogLimit is reached. Routes should also limit it per client with
+// RateLimit.
+func CSPReport(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCSPReportSize))
+	if err != nil {
+		http.Error(w, "report too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var violations []cspViolation
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/reports+json":
+		var reports []struct {
+			Type string       `json:"type"`
+			Body cspViolation `json:"body"`
+		}
+		err = json.Unmarshal(body, &reports)
+		for _, rep := range reports {
+			if rep.Type == "csp-violation" {
+				violations = append(violations, rep.Body)
+			}
+		}
+	default:
+		var rep struct {
+			Report cspViolation `json:"csp-report"`
+		}
+		err = json.Unmarshal(body, &rep)
+		violations = append(violations, rep.Report)
+	}
+	if err != nil {
+		http.Error(w, "malformed report", http.StatusBadRequest)
+		return
+	}
+
+	logger := logging.FromContext(r.Context())
+	for _, v := range violations[:min(len(violations), maxCSPViolationsPerBatch)] {
+		if ok, _ := cspLogLimit.Allow(""); !ok {
+			break
+		}
+		logger.LogAttrs(r.Context(), slog.LevelWarn, "csp violation", v.attrs()...)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ContentSecurityPolicy joins directives into a policy, reporting
+// violations to CSPReportPath.
+func ContentSecurityPolicy(directives ...string) string {
+	return strings.Join(append(directives, "report-uri "+CSPReportPath, "report-to csp"), "; ")
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"portfolio/logging"
+)
+
+// postCSPReport posts body to CSPReport and returns the response and the
+// number of violations logged.
+func postCSPReport(contentType, body string) (*httptest.ResponseRecorder, int) {
+	var log bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&log, nil))
+	r := httptest.NewRequest(http.MethodPost, CSPReportPath, strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	r = r.WithContext(logging.NewContext(r.Context(), logger))
+	rec := httptest.NewRecorder()
+	CSPReport(rec, r)
+	return rec, strings.Count(log.String(), `msg="csp violation"`)
+}
+
+func batch(n int) string {
+	reports := make([]string, n)
+	for i := range reports {
+		reports[i] = `{"type":"csp-violation","body":{"documentURL":"https://example.com/","effectiveDirective":"script-src"}}`
+	}
+	return "[" + strings.Join(reports, ",") + "]"
+}
+
+func TestCSPReport(t *testing.T) {
+	defer func(l *RateLimiter) { cspLogLimit = l }(cspLogLimit)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		logged      int
+	}{
+		{"legacy report", "application/csp-report", `{"csp-report":{"document-uri":"https://example.com/","violated-directive":"script-src"}}`, http.StatusNoContent, 1},
+		{"reporting api batch", "application/reports+json", batch(2), http.StatusNoContent, 2},
+		{"batch capped", "application/reports+json", batch(8), http.StatusNoContent, maxCSPViolationsPerBatch},
+		{"other report types", "application/reports+json", `[{"type":"deprecation","body":{}}]`, http.StatusNoContent, 0},
+		{"malformed", "application/csp-report", `{`, http.StatusBadRequest, 0},
+		{"too large", "application/reports+json", batch(100), http.StatusRequestEntityTooLarge, 0},
+	}
+	for _, tt := range tests {
+		cspLogLimit = NewRateLimiter(100, time.Minute)
+		rec, logged := postCSPReport(tt.contentType, tt.body)
+		if rec.Code != tt.status {
+			t.Errorf("%s: got %d, want %d", tt.name, rec.Code, tt.status)
+		}
+		if logged != tt.logged {
+			t.Errorf("%s: logged %d violations, want %d", tt.name, logged, tt.logged)
+		}
+	}
+}
+
+func TestCSPReportLogLimit(t *testing.T) {
+	defer func(l *RateLimiter) { cspLogLimit = l }(cspLogLimit)
+	cspLogLimit = NewRateLimiter(3, time.Minute)
+
+	total := 0
+	for range 3 {
+		rec, logged := postCSPReport("application/reports+json", batch(2))
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("got %d, want 204 even when not logged", rec.Code)
+		}
+		total += logged
+	}
+	if total != 3 {
+		t.Errorf("logged %d violations, want the limit of 3", total)
+	}
+}
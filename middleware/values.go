@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+
+	"portfolio/reqctx"
+)
+
+// ThemeCookie holds the visitor's chosen color theme.
+const ThemeCookie = "theme"
+
+// Values stores a fresh reqctx.Values in the request context with a new CSP
+// nonce, the visitor's theme and their preferred supported locale. The page
+// is filled in once the router has matched the request, and the CSRF token
+// by CSRFToken.
+func Values(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := &reqctx.Values{
+			Nonce:  newNonce(),
+			Theme:  theme(r),
+			Locale: locale(r.Header.Get("Accept-Language")),
+		}
+		next.ServeHTTP(w, r.WithContext(reqctx.NewContext(r.Context(), v)))
+	})
+}
+
+func newNonce() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+// theme returns the theme stored in ThemeCookie, defaulting to dark.
+func theme(r *http.Request) string {
+	if c, err := r.Cookie(ThemeCookie); err == nil && c.Value == reqctx.ThemeLight {
+		return reqctx.ThemeLight
+	}
+	return reqctx.ThemeDark
+}
+
+// locale returns the first supported language in an Accept-Language header,
+// matching on the primary subtag. Quality values are not weighed; browsers
+// list languages in preference order.
+func locale(header string) string {
+	for part := range strings.SplitSeq(header, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if slices.Contains(reqctx.SupportedLocales, primary) {
+			return primary
+		}
+	}
+	return reqctx.DefaultLocale
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// maxRedirects bounds the redirects followed for one delivery.
+const maxRedirects = 3
+
+var errTooManyRedirects = errors.New("webhook redirected too many times")
+
+// NewClient returns the HTTP client webhook deliveries are sent with.
+// ValidateTarget only sees the URL a subscriber entered, so the client
+// checks again where requests actually go: every address the dialer
+// connects to, after DNS resolution, and every redirect. Unless allowLocal
+// is set, connections to loopback, private and link-local addresses (such
+// as a cloud metadata endpoint) fail, however the host name resolves.
+// Proxy settings from the environment are ignored, since a proxy would hide
+// the real destination from the dialer.
+func NewClient(allowLocal bool, timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowLocal {
+		dialer.Control = rejectLocalAddr
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: otelhttp.NewTransport(transport),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return errTooManyRedirects
+			}
+			return validateURL(req.URL, allowLocal)
+		},
+	}
+}
+
+// rejectLocalAddr is a net.Dialer Control function. It runs after the host
+// name has been resolved, with the address about to be connected to.
+func rejectLocalAddr(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isLocalIP(ip) {
+		return fmt.Errorf("notify: %w (%s)", errLocalURL, host)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// The dialer must refuse local addresses even when the URL passed
+// validation, as it does when a public host name resolves to one.
+func TestClientRefusesLocalAddresses(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+
+	n := &JSONNotifier{URL: srv.URL, Client: NewClient(false, 5*time.Second)}
+	err := n.Notify(context.Background(), Message{Title: "t"})
+	if !errors.Is(err, errLocalURL) {
+		t.Fatalf("Notify to %s = %v, want errLocalURL", srv.URL, err)
+	}
+	if retryable(err) {
+		t.Error("a refused delivery is retryable")
+	}
+	if hits != 0 {
+		t.Errorf("server received %d requests", hits)
+	}
+}
+
+// A notifier built without a Client must not fall back to an unchecked one.
+func TestNilClientRefusesLocalAddresses(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+
+	for _, n := range []Notifier{
+		&NtfyNotifier{URL: srv.URL},
+		&ChatNotifier{URL: srv.URL},
+		&JSONNotifier{URL: srv.URL},
+	} {
+		if err := n.Notify(context.Background(), Message{Title: "t"}); !errors.Is(err, errLocalURL) {
+			t.Errorf("%T without a Client = %v, want errLocalURL", n, err)
+		}
+	}
+	if hits != 0 {
+		t.Errorf("server received %d requests", hits)
+	}
+}
+
+func TestClientAllowLocal(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	n := &JSONNotifier{URL: srv.URL, Client: NewClient(true, 5*time.Second)}
+	if err := n.Notify(context.Background(), Message{Title: "t"}); err != nil {
+		t.Fatalf("Notify with allowLocal = %v", err)
+	}
+}
+
+func TestClientChecksRedirects(t *testing.T) {
+	client := NewClient(false, time.Second)
+	via := []*http.Request{{URL: mustParse(t, "https://hooks.example.com/a")}}
+
+	tests := []struct {
+		to   string
+		want error
+	}{
+		{"https://hooks.example.com/b", nil},
+		{"http://hooks.example.com/b", errInvalidURL},
+		{"https://169.254.169.254/latest/meta-data", errLocalURL},
+		{"https://localhost/admin", errLocalURL},
+	}
+	for _, tt := range tests {
+		req := &http.Request{URL: mustParse(t, tt.to)}
+		if got := client.CheckRedirect(req, via); got != tt.want {
+			t.Errorf("redirect to %s = %v, want %v", tt.to, got, tt.want)
+		}
+	}
+
+	long := make([]*http.Request, maxRedirects)
+	req := &http.Request{URL: mustParse(t, "https://hooks.example.com/c")}
+	if got := client.CheckRedirect(req, long); got != errTooManyRedirects {
+		t.Errorf("redirect after %d = %v, want errTooManyRedirects", maxRedirects, got)
+	}
+}
+
+func TestClientStopsRedirectLoops(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, srv.URL+"/again", http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	n := &JSONNotifier{URL: srv.URL, Client: NewClient(true, 5*time.Second)}
+	err := n.Notify(context.Background(), Message{Title: "t"})
+	if !errors.Is(err, errTooManyRedirects) {
+		t.Fatalf("Notify = %v, want errTooManyRedirects", err)
+	}
+}
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// mailTimeout bounds one delivery when ctx has no earlier deadline.
+const mailTimeout = 30 * time.Second
+
+// Mailer sends plain-text email through an SMTP relay. The connection is
+// upgraded with STARTTLS whenever the relay offers it, and credentials are
+// only sent over TLS or to localhost, as smtp.PlainAuth enforces.
+type Mailer struct {
+	Addr     string // relay host:port
+	Username string // no authentication when empty
+	Password string
+	From     string
+}
+
+// Send delivers msg to the address to. The subject is msg.Title and the
+// body is msg.Body followed by msg.Link.
+func (m *Mailer) Send(ctx context.Context, to string, msg Message) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("notify: sender address: %w", err)
+	}
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("notify: recipient address: %w", err)
+	}
+	host, _, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(mailTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(rcpt.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(formatMail(from, rcpt, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// formatMail renders msg as a MIME message. Header values are encoded, so
+// a title cannot inject headers, and the body is quoted-printable so long
+// or non-ASCII lines survive any relay.
+func formatMail(from, to *mail.Address, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := msg.Body
+	if msg.Link != "" {
+		body += "\n\n" + msg.Link
+	}
+	qp := quotedprintable.NewWriter(&buf)
+	_, _ = qp.Write([]byte(body))
+	_ = qp.Close()
+	return buf.Bytes()
+}
+
+// EmailNotifier emails messages to one address.
+type EmailNotifier struct {
+	Mailer *Mailer
+	To     string
+}
+
+// Notify implements Notifier.
+func (n *EmailNotifier) Notify(ctx context.Context, msg Message) error {
+	return n.Mailer.Send(ctx, n.To, msg)
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// fakeRelay accepts one SMTP session on a loopback listener and reports the
+// envelope and message it received. rcptCode is the reply to RCPT TO.
+func fakeRelay(t *testing.T, rcptCode int) (addr string, got <-chan []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var session []string
+		defer func() { ch <- session }()
+
+		_ = tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, _, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250 localhost")
+			case "MAIL":
+				session = append(session, line)
+				_ = tp.PrintfLine("250 OK")
+			case "RCPT":
+				session = append(session, line)
+				_ = tp.PrintfLine("%d recipient", rcptCode)
+			case "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				session = append(session, string(data))
+				_ = tp.PrintfLine("250 queued")
+			case "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+	return ln.Addr().String(), ch
+}
+
+func TestMailerSend(t *testing.T) {
+	addr, got := fakeRelay(t, 250)
+	m := &Mailer{Addr: addr, From: "Schedules <schedules@example.com>"}
+	msg := Message{
+		Title: "Rescheduled: Hawks vs Owls\r\nBcc: victim@example.com",
+		Body:  "Saturday 9:00 AM → Sunday 10:00 AM",
+		Link:  "https://example.com/soccer",
+	}
+	if err := m.Send(context.Background(), "fan@example.com", msg); err != nil {
+		t.Fatal(err)
+	}
+
+	session := <-got
+	if len(session) != 3 {
+		t.Fatalf("session = %q, want MAIL, RCPT and DATA", session)
+	}
+	if session[0] != "MAIL FROM:<schedules@example.com>" {
+		t.Errorf("MAIL = %q", session[0])
+	}
+	if session[1] != "RCPT TO:<fan@example.com>" {
+		t.Errorf("RCPT = %q", session[1])
+	}
+
+	tp := textproto.NewReader(bufio.NewReader(strings.NewReader(session[2])))
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("Bcc") != "" {
+		t.Errorf("title injected a Bcc header: %q", header.Get("Bcc"))
+	}
+	if got := header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+		t.Errorf("Content-Transfer-Encoding = %q", got)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(tp.R))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := msg.Body + "\n\n" + msg.Link; strings.TrimRight(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n") != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestMailerSendRejected(t *testing.T) {
+	addr, _ := fakeRelay(t, 550)
+	m := &Mailer{Addr: addr, From: "schedules@example.com"}
+	err := m.Send(context.Background(), "nobody@example.com", Message{Title: "x"})
+	if err == nil {
+		t.Fatal("Send succeeded, want the relay's rejection")
+	}
+	if retryable(err) {
+		t.Errorf("permanent SMTP rejection %v is retryable", err)
+	}
+}
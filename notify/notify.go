@@ -0,0 +1,117 @@
+// Package notify delivers schedule-change messages to subscriber targets such
+// as ntfy topics, Discord/Slack incoming webhooks and generic JSON endpoints.
+package notify
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Message is a single notification. Webhook targets render Title and Body
+// in their own format; generic JSON targets receive the Message as-is.
+type Message struct {
+	Event string `json:"event"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Link  string `json:"link,omitempty"`
+	Data  any    `json:"data,omitempty"`
+}
+
+// Notifier delivers a Message to one destination.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Kind identifies the payload format expected by a target.
+type Kind string
+
+// Supported target kinds.
+const (
+	KindNtfy Kind = "ntfy" // ntfy-style topic URL, plain-text body
+	KindChat Kind = "chat" // Discord/Slack-compatible incoming webhook
+	KindJSON Kind = "json" // generic JSON POST of the Message
+)
+
+// Target is a subscriber's webhook destination.
+type Target struct {
+	Kind Kind   `json:"kind"`
+	URL  string `json:"url"`
+}
+
+var (
+	errUnknownKind = errors.New("unknown webhook type")
+	errInvalidURL  = errors.New("webhook URL must be an absolute https URL")
+	errLocalURL    = errors.New("webhook URL must not point at a local or private address")
+)
+
+// ValidateTarget checks that t can be delivered to. Only https URLs on
+// public hosts are accepted unless allowLocal is set, which also permits
+// plain http to loopback and private addresses so a local HTTP stand-in can
+// receive notifications during development. Host names are not resolved
+// here; the client from NewClient checks the addresses it connects to.
+func ValidateTarget(t Target, allowLocal bool) error {
+	switch t.Kind {
+	case KindNtfy, KindChat, KindJSON:
+	default:
+		return errUnknownKind
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil {
+		return errInvalidURL
+	}
+	return validateURL(u, allowLocal)
+}
+
+// validateURL checks a target URL, or a redirect from one.
+func validateURL(u *url.URL, allowLocal bool) error {
+	if u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errInvalidURL
+	}
+	if allowLocal {
+		return nil
+	}
+	if u.Scheme != "https" {
+		return errInvalidURL
+	}
+	if isLocalHost(u.Hostname()) {
+		return errLocalURL
+	}
+	return nil
+}
+
+func isLocalHost(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && isLocalIP(ip)
+}
+
+// isLocalIP reports whether ip is not a public unicast address.
+func isLocalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which is
+// not public either.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// New returns a Notifier for t that sends requests with client.
+func New(t Target, client *http.Client) (Notifier, error) {
+	switch t.Kind {
+	case KindNtfy:
+		return &NtfyNotifier{URL: t.URL, Client: client}, nil
+	case KindChat:
+		return &ChatNotifier{URL: t.URL, Client: client}, nil
+	case KindJSON:
+		return &JSONNotifier{URL: t.URL, Client: client}, nil
+	default:
+		return nil, errUnknownKind
+	}
+}
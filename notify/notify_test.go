@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"net"
+	"testing"
+)
+
+func TestValidateTarget(t *testing.T) {
+	tests := []struct {
+		target     Target
+		allowLocal bool
+		want       error
+	}{
+		{Target{KindNtfy, "https://ntfy.sh/topic"}, false, nil},
+		{Target{KindChat, "https://discord.com/api/webhooks/1/x"}, false, nil},
+		{Target{KindJSON, "https://93.184.216.34/hook"}, false, nil},
+		{Target{"sms", "https://ntfy.sh/topic"}, false, errUnknownKind},
+		{Target{KindNtfy, "http://ntfy.sh/topic"}, false, errInvalidURL},
+		{Target{KindNtfy, "ftp://ntfy.sh/topic"}, false, errInvalidURL},
+		{Target{KindNtfy, "ntfy.sh/topic"}, false, errInvalidURL},
+		{Target{KindNtfy, "https://localhost/x"}, false, errLocalURL},
+		{Target{KindNtfy, "https://api.LOCALHOST/x"}, false, errLocalURL},
+		{Target{KindNtfy, "https://127.0.0.1/x"}, false, errLocalURL},
+		{Target{KindNtfy, "https://10.1.2.3/x"}, false, errLocalURL},
+		{Target{KindNtfy, "https://169.254.169.254/latest/meta-data"}, false, errLocalURL},
+		{Target{KindNtfy, "https://[::1]/x"}, false, errLocalURL},
+		{Target{KindNtfy, "https://[fd00::1]/x"}, false, errLocalURL},
+		{Target{KindNtfy, "https://100.64.0.1/x"}, false, errLocalURL},
+		{Target{KindNtfy, "http://127.0.0.1:8090/x"}, true, nil},
+		{Target{KindNtfy, "ftp://127.0.0.1/x"}, true, errInvalidURL},
+	}
+	for _, tt := range tests {
+		if got := ValidateTarget(tt.target, tt.allowLocal); got != tt.want {
+			t.Errorf("ValidateTarget(%v, %t) = %v, want %v", tt.target, tt.allowLocal, got, tt.want)
+		}
+	}
+}
+
+func TestIsLocalIP(t *testing.T) {
+	for _, addr := range []string{
+		"127.0.0.1", "10.0.0.1", "172.16.5.4", "192.168.1.1", "169.254.169.254", "0.0.0.0",
+		"100.64.0.1", "224.0.0.1", "::1", "::", "fe80::1", "fc00::1", "::ffff:127.0.0.1",
+	} {
+		if !isLocalIP(net.ParseIP(addr)) {
+			t.Errorf("isLocalIP(%s) = false, want true", addr)
+		}
+	}
+	for _, addr := range []string{"93.184.216.34", "1.1.1.1", "100.128.0.1", "2606:4700::1111"} {
+		if isLocalIP(net.ParseIP(addr)) {
+			t.Errorf("isLocalIP(%s) = true, want false", addr)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/textproto"
+	"time"
+)
+
+// Retry wraps a Notifier with exponential backoff. Transport errors,
+// 429/5xx responses and temporary (4xx) SMTP replies are retried; other
+// failures, including deliveries the client refused to send, are returned
+// immediately.
+type Retry struct {
+	Notifier  Notifier
+	Attempts  int           // total attempts, including the first
+	BaseDelay time.Duration // delay before the first retry
+	MaxDelay  time.Duration // upper bound for any single delay
+}
+
+// WithRetry wraps n with the default retry policy: 5 attempts starting at
+// one second and capped at one minute.
+func WithRetry(n Notifier) *Retry {
+	return &Retry{Notifier: n, Attempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute}
+}
+
+// Notify implements Notifier.
+func (r *Retry) Notify(ctx context.Context, msg Message) error {
+	delay := r.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := r.Notifier.Notify(ctx, msg)
+		if err == nil || attempt >= r.Attempts || !retryable(err) {
+			return err
+		}
+
+		// Equal jitter (half the delay, plus up to half again at random) keeps
+		// many subscribers from retrying in lockstep.
+		wait := delay/2 + rand.N(delay/2+1)
+		var serr *StatusError
+		if errors.As(err, &serr) && serr.RetryAfter > wait {
+			wait = serr.RetryAfter
+		}
+		wait = min(wait, r.MaxDelay)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(delay*2, r.MaxDelay)
+	}
+}
+
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	// Refused destinations and redirects will be refused again.
+	if errors.Is(err, errLocalURL) || errors.Is(err, errInvalidURL) || errors.Is(err, errTooManyRedirects) {
+		return false
+	}
+	var serr *StatusError
+	if errors.As(err, &serr) {
+		return serr.Temporary()
+	}
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code < 500
+	}
+	return true
+}
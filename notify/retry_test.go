@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+	"time"
+)
+
+// scripted fails with errs in order, then succeeds.
+type scripted struct {
+	errs  []error
+	calls int
+}
+
+func (s *scripted) Notify(context.Context, Message) error {
+	s.calls++
+	if s.calls <= len(s.errs) {
+		return s.errs[s.calls-1]
+	}
+	return nil
+}
+
+func fastRetry(n Notifier) *Retry {
+	return &Retry{Notifier: n, Attempts: 4, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestRetry(t *testing.T) {
+	transient := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	notFound := &StatusError{StatusCode: http.StatusNotFound}
+	mailboxUnavailable := &textproto.Error{Code: 550}
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{"success", nil, 1, nil},
+		{"recovers after 5xx", []error{transient, transient}, 3, nil},
+		{"recovers after 429", []error{&StatusError{StatusCode: http.StatusTooManyRequests}}, 2, nil},
+		{"recovers after transport error", []error{errors.New("connection reset")}, 2, nil},
+		{"gives up after Attempts", []error{transient, transient, transient, transient, transient}, 4, transient},
+		{"4xx is permanent", []error{notFound}, 1, notFound},
+		{"refused destination is permanent", []error{errLocalURL}, 1, errLocalURL},
+		{"deadline is permanent", []error{context.DeadlineExceeded}, 1, context.DeadlineExceeded},
+		{"SMTP 4xx recovers", []error{&textproto.Error{Code: 451}}, 2, nil},
+		{"SMTP 5xx is permanent", []error{mailboxUnavailable}, 1, mailboxUnavailable},
+	}
+	for _, tt := range tests {
+		n := &scripted{errs: tt.errs}
+		err := fastRetry(n).Notify(context.Background(), Message{})
+		if n.calls != tt.wantCalls {
+			t.Errorf("%s: %d attempts, want %d", tt.name, n.calls, tt.wantCalls)
+		}
+		if err != tt.wantErr {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRetryHonorsRetryAfterUpToMaxDelay(t *testing.T) {
+	n := &scripted{errs: []error{&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}}}
+	r := fastRetry(n)
+	r.MaxDelay = 20 * time.Millisecond
+
+	start := time.Now()
+	if err := r.Notify(context.Background(), Message{}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < r.MaxDelay || elapsed > time.Second {
+		t.Errorf("waited %s, want about MaxDelay (%s)", elapsed, r.MaxDelay)
+	}
+}
+
+func TestRetryStopsWhenCanceled(t *testing.T) {
+	n := &scripted{errs: []error{&StatusError{StatusCode: http.StatusBadGateway}}}
+	r := &Retry{Notifier: n, Attempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Notify(ctx, Message{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if n.calls != 1 {
+		t.Errorf("%d attempts, want 1", n.calls)
+	}
+}
+
+func TestSendReadsRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"7", 7 * time.Second},
+		{"", 0},
+		{"0", 0},
+		{"soon", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0}, // HTTP dates fall back to backoff
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tt.header != "" {
+				w.Header().Set("Retry-After", tt.header)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		err := (&NtfyNotifier{URL: srv.URL, Client: srv.Client()}).Notify(context.Background(), Message{Title: "t"})
+		srv.Close()
+
+		var serr *StatusError
+		if !errors.As(err, &serr) {
+			t.Fatalf("Retry-After %q: error = %v, want a StatusError", tt.header, err)
+		}
+		if serr.StatusCode != http.StatusTooManyRequests || serr.RetryAfter != tt.want {
+			t.Errorf("Retry-After %q: got %d, %s; want 429, %s", tt.header, serr.StatusCode, serr.RetryAfter, tt.want)
+		}
+		if !serr.Temporary() {
+			t.Errorf("429 is not temporary")
+		}
+	}
+}
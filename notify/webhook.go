@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatusError reports a non-2xx response from a webhook target.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration // from the Retry-After header, if any
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("notify: target responded %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Temporary reports whether the request may succeed if retried.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// NtfyNotifier publishes to an ntfy-style topic URL. The body is sent as
+// plain text with the title and click-through link in headers.
+type NtfyNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify implements Notifier.
+func (n *NtfyNotifier) Notify(ctx context.Context, msg Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, strings.NewReader(msg.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Title", msg.Title)
+	if msg.Link != "" {
+		req.Header.Set("Click", msg.Link)
+	}
+	return send(n.Client, req)
+}
+
+// ChatNotifier posts to a Discord- or Slack-compatible incoming webhook.
+// Discord reads "content" and Slack reads "text"; each ignores the other.
+type ChatNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify implements Notifier.
+func (n *ChatNotifier) Notify(ctx context.Context, msg Message) error {
+	text := "**" + msg.Title + "**\n" + msg.Body
+	if msg.Link != "" {
+		text += "\n" + msg.Link
+	}
+	return postJSON(ctx, n.Client, n.URL, map[string]string{"content": text, "text": text})
+}
+
+// JSONNotifier posts the Message as JSON to a generic endpoint.
+type JSONNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify implements Notifier.
+func (n *JSONNotifier) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, n.Client, n.URL, msg)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return send(client, req)
+}
+
+// defaultClient delivers for notifiers built without a Client. It keeps the
+// address checks of NewClient; http.DefaultClient would skip them.
+var defaultClient = NewClient(false, 10*time.Second)
+
+func send(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = defaultClient
+	}
+	req.Header.Set("User-Agent", "craigdevjohnson-portfolio-notify/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		serr := &StatusError{StatusCode: resp.StatusCode}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			serr.RetryAfter = time.Duration(secs) * time.Second
+		}
+		return serr
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Package reqctx carries per-request view data through context.Context so
+// templ components can read it without every Props struct threading it
+// through. Components access it through the implicit ctx, e.g.
+// reqctx.Nonce(ctx).
+package reqctx
+
+import "context"
+
+// Themes accepted by Values.Theme.
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// DefaultLocale is used when the visitor's preferred languages are not
+// supported.
+const DefaultLocale = "en"
+
+// SupportedLocales lists the locales the site has content for.
+var SupportedLocales = []string{DefaultLocale}
+
+// Values is the request-scoped view data.
+type Values struct {
+	Page      string // nav key of the matched route, e.g. "skills"; "" when unrouted
+	Nonce     string // CSP nonce for inline and external script tags
+	CSRFToken string // token echoed by forms and HTMX requests on unsafe methods
+	Theme     string // ThemeDark or ThemeLight
+	Locale    string // BCP 47 tag from SupportedLocales
+	NoSoccer  bool   // the soccer tool is not served, as in a static export without it
+}
+
+type valuesKey struct{}
+
+// NewContext returns a copy of ctx carrying v. Middleware further down the
+// chain may fill in fields of v before the handler renders.
+func NewContext(ctx context.Context, v *Values) context.Context {
+	return context.WithValue(ctx, valuesKey{}, v)
+}
+
+// From returns the values carried by ctx. Outside a request, such as when
+// rendering in a background job, it returns defaults.
+func From(ctx context.Context) *Values {
+	if v, ok := ctx.Value(valuesKey{}).(*Values); ok {
+		return v
+	}
+	return &Values{Theme: ThemeDark, Locale: DefaultLocale}
+}
+
+// Page returns the nav key of the current page.
+func Page(ctx context.Context) string { return From(ctx).Page }
+
+// Nonce returns the CSP nonce for the current response.
+func Nonce(ctx context.Context) string { return From(ctx).Nonce }
+
+// CSRFToken returns the visitor's CSRF token.
+func CSRFToken(ctx context.Context) string { return From(ctx).CSRFToken }
+
+// Theme returns the visitor's color theme.
+func Theme(ctx context.Context) string { return From(ctx).Theme }
+
+// Locale returns the visitor's locale.
+func Locale(ctx context.Context) string { return From(ctx).Locale }
+
+// NoSoccer reports whether links to the soccer tool should be left out.
+func NoSoccer(ctx context.Context) bool { return From(ctx).NoSoccer }
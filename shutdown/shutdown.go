@@ -0,0 +1,87 @@
+// Package shutdown coordinates an orderly process exit. Components register
+// hooks as they start, and the hooks run in reverse registration order when
+// the process is asked to stop, so later components that depend on earlier
+// ones are stopped first.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"portfolio/logging"
+)
+
+// Hook stops one component. It should return once the component has
+// stopped or ctx is done, whichever comes first.
+type Hook func(ctx context.Context) error
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Registry holds shutdown hooks. The zero value is ready to use.
+type Registry struct {
+	mu    sync.Mutex
+	hooks []namedHook
+	done  bool
+}
+
+// Register adds a hook that runs when Shutdown is called.
+func (r *Registry) Register(name string, hook Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, namedHook{name: name, hook: hook})
+}
+
+// Go runs fn in a new goroutine and registers a hook that cancels fn's
+// context and waits for it to return.
+func (r *Registry) Go(name string, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		fn(ctx)
+	}()
+	r.Register(name, func(shutdownCtx context.Context) error {
+		cancel()
+		select {
+		case <-stopped:
+			return nil
+		case <-shutdownCtx.Done():
+			return shutdownCtx.Err()
+		}
+	})
+}
+
+// Shutdown runs every registered hook in reverse registration order, one at
+// a time, sharing the deadline of ctx. A failing hook does not prevent the
+// remaining hooks from running; all failures are returned together.
+// Calling Shutdown more than once has no effect.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if r.done {
+		r.mu.Unlock()
+		return nil
+	}
+	r.done = true
+	hooks := r.hooks
+	r.mu.Unlock()
+
+	logger := logging.FromContext(ctx)
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		start := time.Now()
+		if err := h.hook(ctx); err != nil {
+			logger.Error("shutdown: hook failed", "hook", h.name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+		logger.Info("shutdown: stopped", "hook", h.name, "duration", time.Since(start))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,168 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"portfolio/logging"
+)
+
+func quietContext(t *testing.T) context.Context {
+	t.Helper()
+	return logging.NewContext(t.Context(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestShutdown(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name      string
+		hooks     []string // hook names; "fail" returns errFailed
+		wantOrder []string
+		wantErr   bool
+	}{
+		{"no hooks", nil, nil, false},
+		{"reverse order", []string{"a", "b", "c"}, []string{"c", "b", "a"}, false},
+		{"failure does not stop the rest", []string{"a", "fail", "c"}, []string{"c", "fail", "a"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Registry
+			var order []string
+			for _, name := range tt.hooks {
+				r.Register(name, func(context.Context) error {
+					order = append(order, name)
+					if name == "fail" {
+						return errFailed
+					}
+					return nil
+				})
+			}
+
+			err := r.Shutdown(quietContext(t))
+			if !slices.Equal(order, tt.wantOrder) {
+				t.Errorf("ran %q, want %q", order, tt.wantOrder)
+			}
+			if got := errors.Is(err, errFailed); got != tt.wantErr {
+				t.Errorf("Shutdown() = %v, want failure %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// The hooks share one deadline: a slow hook leaves less time for the ones
+// after it rather than each getting its own budget.
+func TestShutdownSharesDeadline(t *testing.T) {
+	var r Registry
+	var deadlines []time.Time
+	for range 3 {
+		r.Register("hook", func(ctx context.Context) error {
+			d, ok := ctx.Deadline()
+			if !ok {
+				t.Error("hook context has no deadline")
+			}
+			deadlines = append(deadlines, d)
+			return nil
+		})
+	}
+	r.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(quietContext(t), 20*time.Millisecond)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	start := time.Now()
+	err := r.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want the slow hook's DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown took %s with a 20ms deadline", elapsed)
+	}
+	if len(deadlines) != 3 {
+		t.Fatalf("%d hooks ran after the slow one, want 3", len(deadlines))
+	}
+	for _, d := range deadlines {
+		if !d.Equal(want) {
+			t.Errorf("hook deadline %s, want the shared %s", d, want)
+		}
+	}
+}
+
+func TestGo(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func(ctx context.Context, returned chan<- struct{})
+		wantErr error
+	}{
+		{
+			name: "cancelled and awaited",
+			fn: func(ctx context.Context, returned chan<- struct{}) {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond) // still cleaning up when cancelled
+				close(returned)
+			},
+		},
+		{
+			name: "already returned",
+			fn: func(_ context.Context, returned chan<- struct{}) {
+				close(returned)
+			},
+		},
+		{
+			name: "ignores cancellation",
+			fn: func(_ context.Context, returned chan<- struct{}) {
+				time.Sleep(time.Second)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Registry
+			returned := make(chan struct{})
+			r.Go("worker", func(ctx context.Context) { tt.fn(ctx, returned) })
+
+			ctx, cancel := context.WithTimeout(quietContext(t), 50*time.Millisecond)
+			defer cancel()
+			err := r.Shutdown(ctx)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Shutdown() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			select {
+			case <-returned:
+			default:
+				t.Error("Shutdown returned before the goroutine did")
+			}
+		})
+	}
+}
+
+func TestShutdownTwice(t *testing.T) {
+	var r Registry
+	calls := 0
+	r.Register("once", func(context.Context) error {
+		calls++
+		return errors.New("failed")
+	})
+
+	ctx := quietContext(t)
+	if err := r.Shutdown(ctx); err == nil {
+		t.Error("first Shutdown() = nil, want the hook's error")
+	}
+	if err := r.Shutdown(ctx); err != nil {
+		t.Errorf("second Shutdown() = %v, want nil", err)
+	}
+	if calls != 1 {
+		t.Errorf("hook ran %d times, want 1", calls)
+	}
+}
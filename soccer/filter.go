@@ -0,0 +1,114 @@
+package soccer
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"portfolio/types"
+)
+
+// GameTimeLayout is the layout of Game.DateTime as reported by the provider.
+const GameTimeLayout = "Mon 01/02/06 03:04 PM"
+
+// filterDateLayout is the layout of the from/to filter parameters, matching
+// the value of an <input type="date">.
+const filterDateLayout = "2006-01-02"
+
+// Venue values accepted by GameFilter.Venue.
+const (
+	VenueHome = "home"
+	VenueAway = "away"
+)
+
+var (
+	errInvalidDate  = errors.New("dates must use the YYYY-MM-DD format")
+	errInvalidRange = errors.New("the from date must not be after the to date")
+	errInvalidVenue = errors.New(`venue must be "home" or "away"`)
+)
+
+// GameTime parses the kick-off time of g in the server's local time zone.
+func GameTime(g types.Game) (time.Time, error) {
+	return time.ParseInLocation(GameTimeLayout, g.DateTime, time.Local)
+}
+
+// ParseFilter reads the from, to, upcoming and venue parameters.
+func ParseFilter(values url.Values) (types.GameFilter, error) {
+	f := types.GameFilter{
+		From:         values.Get("from"),
+		To:           values.Get("to"),
+		UpcomingOnly: values.Get("upcoming") != "",
+		Venue:        values.Get("venue"),
+	}
+	from, to, err := filterBounds(f)
+	if err != nil {
+		return types.GameFilter{}, err
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return types.GameFilter{}, errInvalidRange
+	}
+	if f.Venue != "" && f.Venue != VenueHome && f.Venue != VenueAway {
+		return types.GameFilter{}, errInvalidVenue
+	}
+	return f, nil
+}
+
+// FilterGames returns the games matching f. Games whose kick-off time cannot
+// be parsed are only kept when f has no date constraints.
+func FilterGames(games []types.Game, f types.GameFilter, now time.Time) []types.Game {
+	from, to, err := filterBounds(f)
+	if err != nil {
+		return nil
+	}
+	timed := !from.IsZero() || !to.IsZero() || f.UpcomingOnly
+
+	out := make([]types.Game, 0, len(games))
+	for _, g := range games {
+		switch f.Venue {
+		case VenueHome:
+			if g.Home != g.Team {
+				continue
+			}
+		case VenueAway:
+			if g.Away != g.Team {
+				continue
+			}
+		}
+		if !timed {
+			out = append(out, g)
+			continue
+		}
+		kickoff, err := GameTime(g)
+		if err != nil {
+			continue
+		}
+		if f.UpcomingOnly && kickoff.Before(now) {
+			continue
+		}
+		if !from.IsZero() && kickoff.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !kickoff.Before(to) {
+			continue
+		}
+		out = append(out, g)
+	}
+	return out
+}
+
+// filterBounds returns the half-open [from, to) interval selected by f. The
+// to date is inclusive, so the bound is midnight at the start of the next day.
+func filterBounds(f types.GameFilter) (from, to time.Time, err error) {
+	if f.From != "" {
+		if from, err = time.ParseInLocation(filterDateLayout, f.From, time.Local); err != nil {
+			return time.Time{}, time.Time{}, errInvalidDate
+		}
+	}
+	if f.To != "" {
+		if to, err = time.ParseInLocation(filterDateLayout, f.To, time.Local); err != nil {
+			return time.Time{}, time.Time{}, errInvalidDate
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
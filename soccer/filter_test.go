@@ -0,0 +1,89 @@
+package soccer
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"portfolio/types"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    types.GameFilter
+		wantErr error
+	}{
+		{query: "", want: types.GameFilter{}},
+		{query: "from=2026-01-10&to=2026-01-20", want: types.GameFilter{From: "2026-01-10", To: "2026-01-20"}},
+		{query: "from=2026-01-10&to=2026-01-10", want: types.GameFilter{From: "2026-01-10", To: "2026-01-10"}},
+		{query: "upcoming=1&venue=home", want: types.GameFilter{UpcomingOnly: true, Venue: VenueHome}},
+		{query: "upcoming=on&venue=away", want: types.GameFilter{UpcomingOnly: true, Venue: VenueAway}},
+		{query: "from=01/10/2026", wantErr: errInvalidDate},
+		{query: "to=2026-13-01", wantErr: errInvalidDate},
+		{query: "from=2026-01-21&to=2026-01-20", wantErr: errInvalidRange},
+		{query: "venue=neutral", wantErr: errInvalidVenue},
+		{query: "venue=Home", wantErr: errInvalidVenue},
+	}
+	for _, tt := range tests {
+		values, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseFilter(values)
+		if err != tt.wantErr {
+			t.Errorf("ParseFilter(%q) error = %v, want %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFilter(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFilterGames(t *testing.T) {
+	at := func(s string) string {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm.Format(GameTimeLayout)
+	}
+	games := []types.Game{
+		{ID: "home-early", DateTime: at("2026-01-04 10:00"), Home: "US", Away: "THEM", Team: "US"},
+		{ID: "away-mid", DateTime: at("2026-01-11 23:30"), Home: "THEM", Away: "US", Team: "US"},
+		{ID: "home-late", DateTime: at("2026-01-18 09:00"), Home: "US", Away: "OTHER", Team: "US"},
+		{ID: "other-team", DateTime: at("2026-01-18 12:00"), Home: "OTHER", Away: "US", Team: "OTHER"},
+		{ID: "no-time", DateTime: "TBD", Home: "US", Away: "THEM", Team: "US"},
+	}
+	now, err := time.ParseInLocation("2006-01-02 15:04", "2026-01-11 12:00", time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter types.GameFilter
+		want   []string
+	}{
+		{"no filter keeps everything", types.GameFilter{}, []string{"home-early", "away-mid", "home-late", "other-team", "no-time"}},
+		{"home is relative to each game's team", types.GameFilter{Venue: VenueHome}, []string{"home-early", "home-late", "other-team", "no-time"}},
+		{"away is relative to each game's team", types.GameFilter{Venue: VenueAway}, []string{"away-mid"}},
+		{"upcoming drops kicked-off and untimed games", types.GameFilter{UpcomingOnly: true}, []string{"away-mid", "home-late", "other-team"}},
+		{"to date is inclusive", types.GameFilter{To: "2026-01-11"}, []string{"home-early", "away-mid"}},
+		{"from date starts at midnight", types.GameFilter{From: "2026-01-11"}, []string{"away-mid", "home-late", "other-team"}},
+		{"single day", types.GameFilter{From: "2026-01-18", To: "2026-01-18"}, []string{"home-late", "other-team"}},
+		{"filters combine", types.GameFilter{From: "2026-01-05", Venue: VenueHome, UpcomingOnly: true}, []string{"home-late", "other-team"}},
+		{"invalid date matches nothing", types.GameFilter{From: "soon"}, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, g := range FilterGames(games, tt.filter, now) {
+			got = append(got, g.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: FilterGames(%+v) = %q, want %q", tt.name, tt.filter, got, tt.want)
+		}
+	}
+}
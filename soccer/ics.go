@@ -0,0 +1,57 @@
+package soccer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"portfolio/types"
+)
+
+// gameDuration is the calendar length of a game, including warm-up.
+const gameDuration = 2 * time.Hour
+
+// icsTimeLayout is the floating local date-time format used by DTSTART/DTEND.
+const icsTimeLayout = "20060102T150405"
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// WriteICS writes games as an iCalendar file. Each event's DESCRIPTION names
+// the season using the catalog. Games without a parseable kick-off time are
+// skipped.
+func WriteICS(w io.Writer, games []types.Game, seasons map[string]types.Season) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Craig Johnson Portfolio//Soccer Schedule//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	stamp := time.Now().UTC().Format(icsTimeLayout + "Z")
+	for _, g := range games {
+		kickoff, err := GameTime(g)
+		if err != nil {
+			continue
+		}
+		description := "Season: " + SeasonName(seasons, g.Season)
+		if g.Division != "" {
+			description += "\nDivision: " + g.Division
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+g.ID+"@craigdevjohnson.com",
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+kickoff.Format(icsTimeLayout),
+			"DTEND:"+kickoff.Add(gameDuration).Format(icsTimeLayout),
+			"SUMMARY:"+icsEscaper.Replace(fmt.Sprintf("Soccer: %s vs %s", g.Home, g.Away)),
+			"LOCATION:"+icsEscaper.Replace("Field "+g.Field),
+			"DESCRIPTION:"+icsEscaper.Replace(description),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	// RFC 5545 requires CRLF line endings.
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
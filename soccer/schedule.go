@@ -0,0 +1,173 @@
+// Package soccer holds the schedule logic shared by the soccer tool's HTML
+// fragments and its JSON API.
+package soccer
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"portfolio/metrics"
+	"portfolio/tracing"
+	"portfolio/types"
+)
+
+// ErrInvalidTeamCode is returned when a team code is not a 6-digit number.
+var ErrInvalidTeamCode = errors.New("team codes must be 6-digit numbers")
+
+var teamCodePattern = regexp.MustCompile(`^\d{6}$`)
+
+// ParseTeamCodes splits a raw list of team codes on commas, semicolons or
+// spaces.
+func ParseTeamCodes(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	return strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+}
+
+// ValidateTeamCodes reports the first code that is not a 6-digit number.
+func ValidateTeamCodes(codes []string) (string, error) {
+	for _, code := range codes {
+		if !teamCodePattern.MatchString(code) {
+			return code, ErrInvalidTeamCode
+		}
+	}
+	return "", nil
+}
+
+// FetchGames returns the games scheduled for the given team codes.
+func FetchGames(ctx context.Context, teamCodes []string) (resp types.LambdaGamesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "soccer.FetchGames",
+		trace.WithAttributes(attribute.StringSlice("soccer.team_codes", teamCodes)))
+	defer func() {
+		span.SetAttributes(attribute.Int("soccer.games", len(resp.Games)))
+		tracing.End(span, err)
+	}()
+
+	metrics.SoccerFetches.Inc()
+	resp, err = fetchFromProvider(ctx, teamCodes)
+	if err != nil {
+		metrics.SoccerProviderErrors.Inc()
+	}
+	return resp, err
+}
+
+// Ping reports whether the schedule source is reachable, for readiness
+// checks. While the source is mocked only a done ctx fails it.
+func Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+/*
+========================================
+Mocks
+========================================
+*/
+
+// fetchFromProvider stands in for the request to the schedule provider.
+func fetchFromProvider(_ context.Context, teamCodes []string) (types.LambdaGamesResponse, error) {
+	return mockGames(teamCodes), nil
+}
+
+// mockGames returns a fixed league schedule anchored to the current week so
+// the tool always shows a mix of reported results and upcoming games.
+func mockGames(teamCodes []string) types.LambdaGamesResponse {
+	if len(teamCodes) == 0 {
+		return types.LambdaGamesResponse{Games: []types.Game{}}
+	}
+	nextSunday := mockAnchor()
+	sunday := func(weeks, hour, minute int) string {
+		return nextSunday.AddDate(0, 0, 7*weeks).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute).Format(GameTimeLayout)
+	}
+	score := func(n int) *int { return &n }
+
+	return types.LambdaGamesResponse{
+		Games: []types.Game{
+			{
+				ID:        "168-0412",
+				DateTime:  sunday(-3, 13, 0),
+				Field:     "4",
+				Home:      "YOUR TEAM",
+				Away:      "OPPONENT B",
+				Season:    "168",
+				Division:  "U12 Boys D2",
+				Team:      "YOUR TEAM",
+				HomeScore: score(3),
+				AwayScore: score(1),
+			},
+			{
+				ID:        "168-0431",
+				DateTime:  sunday(-2, 15, 15),
+				Field:     "1",
+				Home:      "OPPONENT C",
+				Away:      "YOUR TEAM",
+				Season:    "168",
+				Division:  "U12 Boys D2",
+				Team:      "YOUR TEAM",
+				HomeScore: score(2),
+				AwayScore: score(2),
+			},
+			{
+				ID:        "168-0447",
+				DateTime:  sunday(-1, 11, 30),
+				Field:     "3",
+				Home:      "OPPONENT A",
+				Away:      "YOUR TEAM",
+				Season:    "168",
+				Division:  "U12 Boys D2",
+				Team:      "YOUR TEAM",
+				HomeScore: score(0),
+				AwayScore: score(1),
+			},
+			{
+				ID:       "168-0502",
+				DateTime: sunday(0, 14, 55),
+				Field:    "3",
+				Home:     "YOUR TEAM",
+				Away:     "OPPONENT A",
+				Season:   "168",
+				Division: "U12 Boys D2",
+				Team:     "YOUR TEAM",
+			},
+			{
+				ID:       "168-0518",
+				DateTime: sunday(1, 16, 30),
+				Field:    "5",
+				Home:     "OPPONENT B",
+				Away:     "YOUR TEAM",
+				Season:   "168",
+				Division: "U12 Boys D2",
+				Team:     "YOUR TEAM",
+			},
+			{
+				ID:       "168-0533",
+				DateTime: sunday(2, 13, 0),
+				Field:    "2",
+				Home:     "YOUR TEAM",
+				Away:     "OPPONENT C",
+				Season:   "168",
+				Division: "U12 Boys D2",
+				Team:     "YOUR TEAM",
+			},
+			{
+				ID:       "169-0014",
+				DateTime: sunday(5, 10, 0),
+				Field:    "1",
+				Home:     "OPPONENT D",
+				Away:     "YOUR TEAM",
+				Season:   "169",
+				Division: "U12 Boys D2",
+				Team:     "YOUR TEAM",
+			},
+		},
+	}
+}
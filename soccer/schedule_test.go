@@ -0,0 +1,74 @@
+package soccer
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseTeamCodes(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"123456", []string{"123456"}},
+		{"123456,234567", []string{"123456", "234567"}},
+		{" 123456; 234567  345678 ", []string{"123456", "234567", "345678"}},
+		{"123456,,234567", []string{"123456", "234567"}},
+	}
+	for _, tt := range tests {
+		if got := ParseTeamCodes(tt.raw); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseTeamCodes(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTeamCodes(t *testing.T) {
+	tests := []struct {
+		codes   []string
+		bad     string
+		wantErr bool
+	}{
+		{nil, "", false},
+		{[]string{"123456", "654321"}, "", false},
+		{[]string{"123456", "12345"}, "12345", true},
+		{[]string{"abcdef"}, "abcdef", true},
+		{[]string{"1234567"}, "1234567", true},
+	}
+	for _, tt := range tests {
+		bad, err := ValidateTeamCodes(tt.codes)
+		if bad != tt.bad || (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTeamCodes(%q) = %q, %v; want %q, error %t", tt.codes, bad, err, tt.bad, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidTeamCode) {
+			t.Errorf("ValidateTeamCodes(%q) error = %v, want ErrInvalidTeamCode", tt.codes, err)
+		}
+	}
+}
+
+// The ICS download and change detection select games by ID, so every mock
+// game needs a distinct one.
+func TestMockGamesHaveIDs(t *testing.T) {
+	games := mockGames([]string{"123456"}).Games
+	if len(games) == 0 {
+		t.Fatal("mockGames returned no games")
+	}
+	seen := make(map[string]bool)
+	for i, g := range games {
+		if g.ID == "" {
+			t.Errorf("game %d has no ID", i)
+		}
+		if seen[g.ID] {
+			t.Errorf("game %d reuses ID %q", i, g.ID)
+		}
+		seen[g.ID] = true
+	}
+}
+
+func TestMockGamesNoTeams(t *testing.T) {
+	if games := mockGames(nil).Games; games == nil || len(games) != 0 {
+		t.Errorf("mockGames(nil).Games = %v, want an empty, non-nil slice", games)
+	}
+}
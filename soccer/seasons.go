@@ -0,0 +1,85 @@
+package soccer
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"time"
+
+	"portfolio/logging"
+	"portfolio/tracing"
+	"portfolio/types"
+)
+
+// seasonsFile is the bundled season catalog used when the provider's season
+// list cannot be scraped.
+//
+//go:embed seasons.json
+var seasonsFile []byte
+
+// Seasons returns the season catalog keyed by season ID. The catalog is
+// scraped from the provider, falling back to the bundled seasons.json.
+func Seasons(ctx context.Context) map[string]types.Season {
+	ctx, span := tracing.Start(ctx, "soccer.Seasons")
+	seasons, err := scrapeSeasons(ctx)
+	tracing.End(span, err)
+	if err != nil {
+		logging.FromContext(ctx).Warn("soccer: scraping seasons failed, using bundled catalog", "err", err)
+		seasons, err = parseSeasons(seasonsFile)
+		if err != nil {
+			logging.FromContext(ctx).Error("soccer: bundled season catalog is invalid", "err", err)
+			return map[string]types.Season{}
+		}
+	}
+	catalog := make(map[string]types.Season, len(seasons))
+	for _, s := range seasons {
+		catalog[s.ID] = s
+	}
+	return catalog
+}
+
+// SeasonName returns the display name of a season, or a generic label when
+// the season is not in the catalog.
+func SeasonName(catalog map[string]types.Season, id string) string {
+	if s, ok := catalog[id]; ok && s.Name != "" {
+		return s.Name
+	}
+	return "Season " + id
+}
+
+func parseSeasons(data []byte) ([]types.Season, error) {
+	var seasons []types.Season
+	if err := json.Unmarshal(data, &seasons); err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+/*
+========================================
+Mocks
+========================================
+*/
+
+// scrapeSeasons stands in for scraping the provider's season list. Like
+// mockGames it is anchored to the current week.
+func scrapeSeasons(_ context.Context) ([]types.Season, error) {
+	sunday := mockAnchor()
+	date := func(weeks int) string {
+		return sunday.AddDate(0, 0, 7*weeks).Format(filterDateLayout)
+	}
+	return []types.Season{
+		{ID: "168", Name: "Fall League", Start: date(-6), End: date(2)},
+		{ID: "169", Name: "Winter League", Start: date(5), End: date(14)},
+	}, nil
+}
+
+// mockAnchor returns the next Sunday after now, at midnight local time.
+func mockAnchor() time.Time {
+	now := time.Now()
+	sunday := time.Date(now.Year(), now.Month(), now.Day()+(7-int(now.Weekday()))%7, 0, 0, 0, 0, time.Local)
+	if !sunday.After(now) {
+		sunday = sunday.AddDate(0, 0, 7)
+	}
+	return sunday
+}
@@ -0,0 +1,104 @@
+package soccer
+
+import (
+	"cmp"
+	"slices"
+
+	"portfolio/types"
+)
+
+// Points awarded per result, following the usual league convention.
+const (
+	pointsWin  = 3
+	pointsDraw = 1
+)
+
+// IsPlayed reports whether the provider has reported a score for g.
+func IsPlayed(g types.Game) bool {
+	return g.HomeScore != nil && g.AwayScore != nil
+}
+
+// SplitResults separates games that have been played from upcoming ones,
+// preserving the provider's order within each group.
+func SplitResults(games []types.Game) (upcoming, results []types.Game) {
+	for _, g := range games {
+		if IsPlayed(g) {
+			results = append(results, g)
+		} else {
+			upcoming = append(upcoming, g)
+		}
+	}
+	return upcoming, results
+}
+
+// Standings computes a W/L/D table for each division from the played games.
+// Teams are ranked by points, then goal difference, then goals scored. A
+// game between two requested teams is fetched once for each of them, so
+// games are counted once per ID.
+func Standings(games []types.Game) []types.DivisionStandings {
+	tables := map[string]map[string]*types.Standing{}
+	var divisions []string
+
+	row := func(division, team string) *types.Standing {
+		table, ok := tables[division]
+		if !ok {
+			table = map[string]*types.Standing{}
+			tables[division] = table
+			divisions = append(divisions, division)
+		}
+		st, ok := table[team]
+		if !ok {
+			st = &types.Standing{Team: team}
+			table[team] = st
+		}
+		return st
+	}
+
+	counted := make(map[string]bool, len(games))
+	for _, g := range games {
+		if !IsPlayed(g) || (g.ID != "" && counted[g.ID]) {
+			continue
+		}
+		counted[g.ID] = true
+		home, away := row(g.Division, g.Home), row(g.Division, g.Away)
+		record(home, *g.HomeScore, *g.AwayScore)
+		record(away, *g.AwayScore, *g.HomeScore)
+	}
+
+	slices.Sort(divisions)
+	out := make([]types.DivisionStandings, 0, len(divisions))
+	for _, division := range divisions {
+		rows := make([]types.Standing, 0, len(tables[division]))
+		for _, st := range tables[division] {
+			rows = append(rows, *st)
+		}
+		slices.SortFunc(rows, func(a, b types.Standing) int {
+			return cmp.Or(
+				cmp.Compare(b.Points, a.Points),
+				cmp.Compare(b.GoalDifference, a.GoalDifference),
+				cmp.Compare(b.GoalsFor, a.GoalsFor),
+				cmp.Compare(a.Team, b.Team),
+			)
+		})
+		out = append(out, types.DivisionStandings{Division: division, Standings: rows})
+	}
+	return out
+}
+
+// record adds a single result to a team's standings row.
+func record(st *types.Standing, scored, conceded int) {
+	st.Played++
+	st.GoalsFor += scored
+	st.GoalsAgainst += conceded
+	st.GoalDifference = st.GoalsFor - st.GoalsAgainst
+	switch {
+	case scored > conceded:
+		st.Won++
+		st.Points += pointsWin
+	case scored == conceded:
+		st.Drawn++
+		st.Points += pointsDraw
+	default:
+		st.Lost++
+	}
+}
@@ -0,0 +1,91 @@
+package soccer
+
+import (
+	"slices"
+	"testing"
+
+	"portfolio/types"
+)
+
+func played(id, division, home, away string, homeScore, awayScore int) types.Game {
+	return types.Game{ID: id, Division: division, Home: home, Away: away, HomeScore: &homeScore, AwayScore: &awayScore}
+}
+
+func TestStandings(t *testing.T) {
+	games := []types.Game{
+		played("1", "D1", "A", "B", 3, 1),
+		played("2", "D1", "B", "C", 2, 2),
+		played("3", "D1", "C", "A", 0, 1),
+		{ID: "4", Division: "D1", Home: "A", Away: "C"}, // not played yet
+		played("5", "D2", "X", "Y", 0, 2),
+	}
+	got := Standings(games)
+
+	want := []types.DivisionStandings{
+		{Division: "D1", Standings: []types.Standing{
+			{Team: "A", Played: 2, Won: 2, GoalsFor: 4, GoalsAgainst: 1, GoalDifference: 3, Points: 6},
+			// C and B are level on points; C has the better goal difference.
+			{Team: "C", Played: 2, Drawn: 1, Lost: 1, GoalsFor: 2, GoalsAgainst: 3, GoalDifference: -1, Points: 1},
+			{Team: "B", Played: 2, Drawn: 1, Lost: 1, GoalsFor: 3, GoalsAgainst: 5, GoalDifference: -2, Points: 1},
+		}},
+		{Division: "D2", Standings: []types.Standing{
+			{Team: "Y", Played: 1, Won: 1, GoalsFor: 2, GoalDifference: 2, Points: 3},
+			{Team: "X", Played: 1, Lost: 1, GoalsAgainst: 2, GoalDifference: -2},
+		}},
+	}
+	if !equalStandings(got, want) {
+		t.Errorf("Standings() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// A game between two requested teams is returned once for each of them and
+// must only be counted once.
+func TestStandingsCountsSharedGamesOnce(t *testing.T) {
+	shared := played("168-0001", "D1", "A", "B", 2, 0)
+	forA, forB := shared, shared
+	forA.Team, forB.Team = "A", "B"
+
+	got := Standings([]types.Game{forA, forB})
+	want := []types.DivisionStandings{{Division: "D1", Standings: []types.Standing{
+		{Team: "A", Played: 1, Won: 1, GoalsFor: 2, GoalDifference: 2, Points: 3},
+		{Team: "B", Played: 1, Lost: 1, GoalsAgainst: 2, GoalDifference: -2},
+	}}}
+	if !equalStandings(got, want) {
+		t.Errorf("Standings() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestStandingsNoResults(t *testing.T) {
+	if got := Standings([]types.Game{{ID: "1", Home: "A", Away: "B"}}); len(got) != 0 {
+		t.Errorf("Standings() = %+v, want no tables", got)
+	}
+}
+
+func TestSplitResults(t *testing.T) {
+	games := []types.Game{
+		{ID: "1"},
+		played("2", "D1", "A", "B", 1, 0),
+		{ID: "3"},
+		played("4", "D1", "B", "A", 0, 0),
+	}
+	upcoming, results := SplitResults(games)
+	ids := func(games []types.Game) []string {
+		var out []string
+		for _, g := range games {
+			out = append(out, g.ID)
+		}
+		return out
+	}
+	if got := ids(upcoming); !slices.Equal(got, []string{"1", "3"}) {
+		t.Errorf("upcoming = %q, want [1 3]", got)
+	}
+	if got := ids(results); !slices.Equal(got, []string{"2", "4"}) {
+		t.Errorf("results = %q, want [2 4]", got)
+	}
+}
+
+func equalStandings(a, b []types.DivisionStandings) bool {
+	return slices.EqualFunc(a, b, func(a, b types.DivisionStandings) bool {
+		return a.Division == b.Division && slices.Equal(a.Standings, b.Standings)
+	})
+}
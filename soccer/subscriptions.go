@@ -0,0 +1,368 @@
+package soccer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"portfolio/logging"
+	"portfolio/notify"
+	"portfolio/tracing"
+	"portfolio/types"
+)
+
+// Subscription registers interest in schedule changes for a set of teams,
+// delivered by email, to one or more webhooks, or both. Email is only sent
+// once the address is confirmed.
+type Subscription struct {
+	ID         string
+	TeamCodes  []string
+	Email      string
+	EmailToken string // confirmation token; empty once Email is confirmed
+	Webhooks   []notify.Target
+}
+
+// EmailConfirmed reports whether sub's email address may be sent updates.
+func (sub Subscription) EmailConfirmed() bool {
+	return sub.Email != "" && sub.EmailToken == ""
+}
+
+// Limits on the store, which anyone can add to. Every subscribed team is
+// polled and every target is posted to, so they bound that work as well as
+// memory.
+const (
+	MaxSubscriptions          = 1000 // across all subscribers
+	MaxSubscriptionsPerTarget = 3    // sharing one webhook URL or email address
+	MaxTeamsPerSubscription   = 10
+)
+
+// Errors returned by Subscriptions.Add.
+var (
+	ErrSubscriptionsFull = errors.New("subscriptions are full")
+	ErrTargetLimit       = errors.New("webhook or email address already has the maximum number of subscriptions")
+	ErrTooManyTeams      = fmt.Errorf("at most %d teams can be subscribed to at once", MaxTeamsPerSubscription)
+)
+
+// Subscriptions is an in-memory subscription store. Subscriptions do not
+// survive a restart.
+type Subscriptions struct {
+	mu   sync.RWMutex
+	subs map[string]Subscription
+}
+
+// NewSubscriptions returns an empty store.
+func NewSubscriptions() *Subscriptions {
+	return &Subscriptions{subs: make(map[string]Subscription)}
+}
+
+// Add stores sub under a new random ID and returns the stored copy. A
+// subscription with an email address gets a new EmailToken, which
+// ConfirmEmail must be called with before email is sent. It fails when sub
+// or the store would exceed one of the limits above.
+func (s *Subscriptions) Add(sub Subscription) (Subscription, error) {
+	if len(sub.TeamCodes) > MaxTeamsPerSubscription {
+		return Subscription{}, ErrTooManyTeams
+	}
+	sub.ID = randomHex(8)
+	sub.EmailToken = ""
+	if sub.Email != "" {
+		sub.EmailToken = randomHex(16)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.subs) >= MaxSubscriptions {
+		return Subscription{}, ErrSubscriptionsFull
+	}
+	if sub.Email != "" && s.countEmail(sub.Email) >= MaxSubscriptionsPerTarget {
+		return Subscription{}, ErrTargetLimit
+	}
+	for _, target := range sub.Webhooks {
+		if s.countURL(target.URL) >= MaxSubscriptionsPerTarget {
+			return Subscription{}, ErrTargetLimit
+		}
+	}
+	s.subs[sub.ID] = sub
+	return sub, nil
+}
+
+// Remove deletes the subscription with the given ID, if any.
+func (s *Subscriptions) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subs, id)
+}
+
+// ConfirmEmail marks the email address of the subscription issued token as
+// confirmed and returns the subscription. Tokens work once.
+func (s *Subscriptions) ConfirmEmail(token string) (Subscription, bool) {
+	if token == "" {
+		return Subscription{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sub := range s.subs {
+		if sub.EmailToken == token {
+			sub.EmailToken = ""
+			s.subs[id] = sub
+			return sub, true
+		}
+	}
+	return Subscription{}, false
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// countEmail returns the number of subscriptions sending to addr, confirmed
+// or not. s.mu must be held.
+func (s *Subscriptions) countEmail(addr string) int {
+	n := 0
+	for _, sub := range s.subs {
+		if strings.EqualFold(sub.Email, addr) {
+			n++
+		}
+	}
+	return n
+}
+
+// countURL returns the number of subscriptions posting to rawURL, ignoring
+// differences that do not change the destination. s.mu must be held.
+func (s *Subscriptions) countURL(rawURL string) int {
+	key := targetKey(rawURL)
+	n := 0
+	for _, sub := range s.subs {
+		if slices.ContainsFunc(sub.Webhooks, func(t notify.Target) bool { return targetKey(t.URL) == key }) {
+			n++
+		}
+	}
+	return n
+}
+
+// targetKey normalizes a webhook URL's letter case, trailing slash and
+// fragment.
+func targetKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// Len returns the number of stored subscriptions.
+func (s *Subscriptions) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.subs)
+}
+
+// TeamCodes returns every team code with at least one subscriber.
+func (s *Subscriptions) TeamCodes() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var codes []string
+	for _, sub := range s.subs {
+		for _, code := range sub.TeamCodes {
+			if !slices.Contains(codes, code) {
+				codes = append(codes, code)
+			}
+		}
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// ForTeam returns the subscriptions that include code.
+func (s *Subscriptions) ForTeam(code string) []Subscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []Subscription
+	for _, sub := range s.subs {
+		if slices.Contains(sub.TeamCodes, code) {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+/*
+========================================
+Change detection
+========================================
+*/
+
+// Change kinds reported by Diff.
+const (
+	ChangeAdded       = "added"
+	ChangeRemoved     = "removed"
+	ChangeRescheduled = "rescheduled"
+	ChangeResult      = "result"
+)
+
+// Change describes how one game differs between two fetches.
+type Change struct {
+	Kind     string      `json:"kind"`
+	Game     types.Game  `json:"game"`
+	Previous *types.Game `json:"previous,omitempty"`
+}
+
+// String formats the change as a single human-readable line.
+func (c Change) String() string {
+	match := c.Game.Home + " vs " + c.Game.Away
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("New game: %s on %s, field %s", match, c.Game.DateTime, c.Game.Field)
+	case ChangeRemoved:
+		return fmt.Sprintf("Removed: %s on %s", match, c.Game.DateTime)
+	case ChangeResult:
+		return fmt.Sprintf("Result: %s %d – %d", match, *c.Game.HomeScore, *c.Game.AwayScore)
+	default:
+		return fmt.Sprintf("Rescheduled: %s now %s, field %s (was %s, field %s)",
+			match, c.Game.DateTime, c.Game.Field, c.Previous.DateTime, c.Previous.Field)
+	}
+}
+
+// Diff compares two fetches of the same schedule by game ID.
+func Diff(before, after []types.Game) []Change {
+	previous := make(map[string]types.Game, len(before))
+	for _, g := range before {
+		previous[g.ID] = g
+	}
+
+	var changes []Change
+	for _, g := range after {
+		old, ok := previous[g.ID]
+		delete(previous, g.ID)
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: ChangeAdded, Game: g})
+		case old.DateTime != g.DateTime || old.Field != g.Field:
+			changes = append(changes, Change{Kind: ChangeRescheduled, Game: g, Previous: &old})
+		case !IsPlayed(old) && IsPlayed(g):
+			changes = append(changes, Change{Kind: ChangeResult, Game: g})
+		}
+	}
+	for _, g := range before {
+		if _, ok := previous[g.ID]; ok {
+			changes = append(changes, Change{Kind: ChangeRemoved, Game: g})
+		}
+	}
+	return changes
+}
+
+/*
+========================================
+Watcher
+========================================
+*/
+
+// Watcher polls the schedules of subscribed teams and notifies subscribers
+// when a schedule changes. The first poll of a team only records a baseline.
+type Watcher struct {
+	Subscriptions *Subscriptions
+	Interval      time.Duration
+	Client        *http.Client   // used for webhook delivery; see notify.NewClient
+	Mailer        *notify.Mailer // used for email delivery; nil disables email
+	BaseURL       string         // site origin used for click-through links
+
+	mu   sync.Mutex
+	last map[string][]types.Game
+}
+
+// Run polls every Interval until ctx is canceled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(ctx)
+		}
+	}
+}
+
+// Check fetches every subscribed team once and delivers any changes.
+func (w *Watcher) Check(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "soccer.Watcher.Check")
+	defer span.End()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.last == nil {
+		w.last = make(map[string][]types.Game)
+	}
+
+	for _, code := range w.Subscriptions.TeamCodes() {
+		resp, err := FetchGames(ctx, []string{code})
+		if err != nil {
+			logging.FromContext(ctx).Warn("soccer: watcher fetch failed", "team", code, "err", err)
+			continue
+		}
+		before, seen := w.last[code]
+		w.last[code] = resp.Games
+		if !seen {
+			continue
+		}
+		if changes := Diff(before, resp.Games); len(changes) > 0 {
+			w.deliver(ctx, code, changes)
+		}
+	}
+}
+
+// deliver sends one message per target concurrently and waits for all of
+// them, so a slow target only delays its own retries.
+func (w *Watcher) deliver(ctx context.Context, code string, changes []Change) {
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = c.String()
+	}
+	msg := notify.Message{
+		Event: "schedule.changed",
+		Title: fmt.Sprintf("Schedule update for team %s", code),
+		Body:  strings.Join(lines, "\n"),
+		Link:  w.BaseURL + "/soccer",
+		Data:  map[string]any{"team": code, "changes": changes},
+	}
+
+	var wg sync.WaitGroup
+	for _, sub := range w.Subscriptions.ForTeam(code) {
+		var notifiers []notify.Notifier
+		if sub.EmailConfirmed() && w.Mailer != nil {
+			notifiers = append(notifiers, notify.WithRetry(&notify.EmailNotifier{Mailer: w.Mailer, To: sub.Email}))
+		}
+		for _, target := range sub.Webhooks {
+			n, err := notify.New(target, w.Client)
+			if err != nil {
+				logging.FromContext(ctx).Error("soccer: subscription has an invalid target", "subscription", sub.ID, "err", err)
+				continue
+			}
+			notifiers = append(notifiers, notify.WithRetry(n))
+		}
+		for _, n := range notifiers {
+			wg.Go(func() {
+				if err := n.Notify(ctx, msg); err != nil {
+					logging.FromContext(ctx).Warn("soccer: notifying subscription failed", "subscription", sub.ID, "err", err)
+				}
+			})
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,170 @@
+package soccer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"portfolio/notify"
+	"portfolio/types"
+)
+
+func webhook(url string) []notify.Target {
+	return []notify.Target{{Kind: notify.KindJSON, URL: url}}
+}
+
+func TestSubscriptionsAdd(t *testing.T) {
+	s := NewSubscriptions()
+	a, err := s.Add(Subscription{TeamCodes: []string{"111111", "222222"}, Webhooks: webhook("https://a.example.com/hook")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID == "" {
+		t.Error("Add did not assign an ID")
+	}
+	if _, err := s.Add(Subscription{TeamCodes: []string{"222222", "333333"}, Webhooks: webhook("https://b.example.com/hook")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+	if got := s.TeamCodes(); !slices.Equal(got, []string{"111111", "222222", "333333"}) {
+		t.Errorf("TeamCodes() = %q", got)
+	}
+	if got := s.ForTeam("222222"); len(got) != 2 {
+		t.Errorf("ForTeam(222222) returned %d subscriptions, want 2", len(got))
+	}
+	if got := s.ForTeam("111111"); len(got) != 1 || got[0].ID != a.ID {
+		t.Errorf("ForTeam(111111) = %+v, want only %s", got, a.ID)
+	}
+}
+
+func TestSubscriptionsPerTargetLimit(t *testing.T) {
+	s := NewSubscriptions()
+	for range MaxSubscriptionsPerTarget {
+		if _, err := s.Add(Subscription{TeamCodes: []string{"111111"}, Webhooks: webhook("https://ntfy.sh/topic")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Spelling the same URL differently does not get around the limit.
+	for _, url := range []string{"https://ntfy.sh/topic", "https://NTFY.SH/topic/", "https://ntfy.sh/topic#x"} {
+		_, err := s.Add(Subscription{TeamCodes: []string{"222222"}, Webhooks: webhook(url)})
+		if !errors.Is(err, ErrTargetLimit) {
+			t.Errorf("Add(%s) error = %v, want ErrTargetLimit", url, err)
+		}
+	}
+	if _, err := s.Add(Subscription{TeamCodes: []string{"222222"}, Webhooks: webhook("https://ntfy.sh/other")}); err != nil {
+		t.Errorf("Add for another URL: %v", err)
+	}
+}
+
+func TestSubscriptionsEmailLimit(t *testing.T) {
+	s := NewSubscriptions()
+	for range MaxSubscriptionsPerTarget {
+		if _, err := s.Add(Subscription{TeamCodes: []string{"111111"}, Email: "parent@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Add(Subscription{TeamCodes: []string{"222222"}, Email: "Parent@Example.com"}); !errors.Is(err, ErrTargetLimit) {
+		t.Errorf("Add error = %v, want ErrTargetLimit", err)
+	}
+}
+
+func TestSubscriptionsConfirmEmail(t *testing.T) {
+	s := NewSubscriptions()
+	hook, err := s.Add(Subscription{TeamCodes: []string{"111111"}, Webhooks: webhook("https://ntfy.sh/topic")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hook.EmailToken != "" || hook.EmailConfirmed() {
+		t.Errorf("webhook-only subscription has email state: %+v", hook)
+	}
+
+	sub, err := s.Add(Subscription{TeamCodes: []string{"111111"}, Email: "parent@example.com", EmailToken: "chosen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.EmailToken == "" || sub.EmailToken == "chosen" {
+		t.Fatalf("EmailToken = %q, want a new random token", sub.EmailToken)
+	}
+	if sub.EmailConfirmed() {
+		t.Error("email confirmed before ConfirmEmail")
+	}
+
+	for _, token := range []string{"", "wrong"} {
+		if _, ok := s.ConfirmEmail(token); ok {
+			t.Errorf("ConfirmEmail(%q) succeeded", token)
+		}
+	}
+	got, ok := s.ConfirmEmail(sub.EmailToken)
+	if !ok || got.ID != sub.ID || !got.EmailConfirmed() {
+		t.Fatalf("ConfirmEmail = %+v, %t", got, ok)
+	}
+	if stored := s.ForTeam("111111"); !slices.ContainsFunc(stored, Subscription.EmailConfirmed) {
+		t.Error("confirmation not stored")
+	}
+	if _, ok := s.ConfirmEmail(sub.EmailToken); ok {
+		t.Error("token worked twice")
+	}
+
+	s.Remove(sub.ID)
+	if got := s.Len(); got != 1 {
+		t.Errorf("Len() after Remove = %d, want 1", got)
+	}
+}
+
+func TestSubscriptionsGlobalLimit(t *testing.T) {
+	s := NewSubscriptions()
+	for i := range MaxSubscriptions {
+		url := fmt.Sprintf("https://hooks.example.com/%d", i)
+		if _, err := s.Add(Subscription{TeamCodes: []string{"111111"}, Webhooks: webhook(url)}); err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+	}
+	_, err := s.Add(Subscription{TeamCodes: []string{"111111"}, Webhooks: webhook("https://hooks.example.com/last")})
+	if !errors.Is(err, ErrSubscriptionsFull) {
+		t.Errorf("Add past the limit: error = %v, want ErrSubscriptionsFull", err)
+	}
+	if s.Len() != MaxSubscriptions {
+		t.Errorf("Len() = %d, want %d", s.Len(), MaxSubscriptions)
+	}
+}
+
+func TestSubscriptionsTeamLimit(t *testing.T) {
+	codes := make([]string, MaxTeamsPerSubscription+1)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("%06d", i)
+	}
+	_, err := NewSubscriptions().Add(Subscription{TeamCodes: codes, Webhooks: webhook("https://a.example.com")})
+	if !errors.Is(err, ErrTooManyTeams) {
+		t.Errorf("error = %v, want ErrTooManyTeams", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := []types.Game{
+		{ID: "1", DateTime: "Sun 01/11/26 02:55 PM", Field: "3"},
+		{ID: "2", DateTime: "Sun 01/18/26 04:30 PM", Field: "5"},
+		{ID: "3", DateTime: "Sun 01/25/26 01:00 PM", Field: "2"},
+	}
+	after := []types.Game{
+		played("1", "D1", "A", "B", 1, 0),
+		{ID: "2", DateTime: "Sun 01/18/26 05:30 PM", Field: "5"},
+		{ID: "4", DateTime: "Sun 02/01/26 01:00 PM", Field: "1"},
+	}
+	after[0].DateTime, after[0].Field = before[0].DateTime, before[0].Field
+
+	var got []string
+	for _, c := range Diff(before, after) {
+		got = append(got, c.Kind+":"+c.Game.ID)
+	}
+	want := []string{"result:1", "rescheduled:2", "added:4", "removed:3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Diff() = %q, want %q", got, want)
+	}
+	if changes := Diff(before, before); len(changes) != 0 {
+		t.Errorf("Diff of identical fetches = %+v", changes)
+	}
+}
@@ -72,17 +72,35 @@
     }
   }
 
-  // Email subscription toggle
-  function setupEmailSubscription() {
-    const emailCheckbox = document.getElementById('email-updates-checkbox')
+  // Soccer results/standings tabs
+  function setupSoccerTabs() {
+    const tabs = document.querySelectorAll('[data-soccer-tabs] [data-tab-target]')
+
+    tabs.forEach(tab => {
+      tab.addEventListener('click', () => {
+        tabs.forEach(other => {
+          const selected = other === tab
+          other.classList.toggle('active', selected)
+          other.setAttribute('aria-selected', selected)
+          const panel = document.getElementById(other.dataset.tabTarget)
+          if (panel) panel.hidden = !selected
+        })
+      })
+    })
+  }
+
+  // Subscription form toggle
+  function setupSubscription() {
+    const subscribeCheckbox = document.getElementById('subscribe-checkbox')
     const subscribeForm = document.getElementById('subscribe-form')
 
-    if (emailCheckbox && subscribeForm) {
-      emailCheckbox.addEventListener('change', () => {
-        subscribeForm.style.display = emailCheckbox.checked ? 'block' : 'none'
-        if (emailCheckbox.checked) {
-          const emailInput = document.getElementById('subscription-email')
-          if (emailInput) emailInput.focus()
+    if (subscribeCheckbox && subscribeForm) {
+      subscribeCheckbox.addEventListener('change', () => {
+        subscribeForm.style.display = subscribeCheckbox.checked ? 'block' : 'none'
+        if (subscribeCheckbox.checked) {
+          const input = document.getElementById('subscription-email') ||
+            document.getElementById('subscription-webhook-url')
+          if (input) input.focus()
         }
       })
     }
@@ -107,8 +125,9 @@
     // Soccer page specific handlers - check for soccer form using data attribute
     if (evt.target.querySelector('[data-soccer-form]') || evt.target.id === 'games-container') {
       showSubscribeSection()
+      setupSoccerTabs()
       setupSoccerSelectAll()
-      setupEmailSubscription()
+      setupSubscription()
     }
 
     // Skills page: re-observe new skill categories after filter swap
@@ -129,8 +148,41 @@
     }
   })
 
+  // Skills page: close a detail panel (delegated, as panels are swapped in)
+  document.body.addEventListener('click', function (evt) {
+    const close = evt.target.closest('.skill-detail-close')
+    if (close) {
+      close.closest('.skill-detail-slot').innerHTML = ''
+    }
+  })
+
+  // Error toasts: htmx does not swap error responses by default. The server
+  // retargets failed HTMX requests to the toast region, so let those through.
+  document.body.addEventListener('htmx:beforeSwap', function (evt) {
+    if (evt.detail.xhr.getResponseHeader('HX-Retarget') === '#toast-region') {
+      evt.detail.shouldSwap = true
+      evt.detail.isError = false
+    }
+  })
+
+  document.body.addEventListener('htmx:afterSwap', function (evt) {
+    if (evt.detail.target.id === 'toast-region') {
+      const toast = evt.detail.target.lastElementChild
+      setTimeout(function () {
+        toast.remove()
+      }, 8000)
+    }
+  })
+
+  document.body.addEventListener('click', function (evt) {
+    const close = evt.target.closest('.toast-close')
+    if (close) {
+      close.closest('.toast').remove()
+    }
+  })
+
   // Initialize on page load (for non-HTMX scenarios)
-  setupEmailSubscription()
+  setupSubscription()
   setupSoccerSelectAll()
 
   // Add intersection observer for scroll animations
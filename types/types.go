@@ -2,56 +2,95 @@ package types
 
 // Experience represents a work experience entry
 type Experience struct {
-	ID               int
-	Position         string
-	Company          string
-	Duration         string
-	Responsibilities string
-	Technologies     []string
-	SkillAreas       string
-	Side             string
+	ID               int      `yaml:"id"`
+	Position         string   `yaml:"position"`
+	Company          string   `yaml:"company"`
+	Duration         string   `yaml:"duration"`
+	Responsibilities string   `yaml:"responsibilities"`
+	Technologies     []string `yaml:"technologies"`
+	SkillAreas       string   `yaml:"skill_areas"` // comma-separated filter keys
+	Side             string   `yaml:"side"`        // timeline side: "left" or "right"
 }
 
 // Skill represents a technical skill
 type Skill struct {
-	ID          int
-	Name        string
-	Icon        string
-	IconPath    string
-	Link        string
-	Proficiency string // "expert", "advanced", "intermediate", "familiar"
-	Featured    bool   // Whether to show in featured skills section
-	Category    string // Category this skill belongs to (populated for featured skills)
-	Description string // Short description for skill detail view
+	ID          int    `yaml:"id"`
+	Name        string `yaml:"name"`
+	Icon        string `yaml:"icon,omitempty"`      // inline SVG markup, used when IconPath is empty
+	IconPath    string `yaml:"icon_path,omitempty"` // URL of an icon image
+	Link        string `yaml:"link,omitempty"`
+	Proficiency string `yaml:"proficiency"`           // "expert", "advanced", "intermediate", "familiar"
+	Featured    bool   `yaml:"featured,omitempty"`    // Whether to show in featured skills section
+	Category    string `yaml:"-"`                     // Category this skill belongs to (populated for featured skills)
+	Description string `yaml:"description,omitempty"` // Short description for skill detail view
 }
 
 // SkillCategory represents a category of skills
 type SkillCategory struct {
-	Name   string
-	Skills []Skill
+	Name   string  `yaml:"name"`
+	Skills []Skill `yaml:"skills"`
 }
 
 // Project represents a project
 type Project struct {
-	ID           int
-	Name         string
-	Intro        string
-	Description  string
-	Technologies []string
-	Image        string
-	GitHubURL    string
-	DemoURL      string
-	Category     string
+	ID           int      `yaml:"id"`
+	Name         string   `yaml:"name"`
+	Intro        string   `yaml:"intro"`
+	Description  string   `yaml:"description"`
+	Technologies []string `yaml:"technologies"`
+	Image        string   `yaml:"image"`
+	GitHubURL    string   `yaml:"github_url,omitempty"`
+	DemoURL      string   `yaml:"demo_url,omitempty"`
+	Category     string   `yaml:"category"`
 }
 
 // Game represents a soccer game
 type Game struct {
-	ID       string `json:"id"`
-	DateTime string `json:"datetime"`
-	Field    string `json:"field"`
-	Home     string `json:"home"`
-	Away     string `json:"away"`
-	Season   string `json:"season"`
+	ID        string `json:"id"`
+	DateTime  string `json:"datetime"`
+	Field     string `json:"field"`
+	Home      string `json:"home"`
+	Away      string `json:"away"`
+	Season    string `json:"season"`
+	Division  string `json:"division"`
+	Team      string `json:"team"`       // the requested team this game was fetched for
+	HomeScore *int   `json:"home_score"` // nil until the result is reported
+	AwayScore *int   `json:"away_score"` // nil until the result is reported
+}
+
+// Standing represents one team's row in a division standings table
+type Standing struct {
+	Team           string `json:"team"`
+	Played         int    `json:"played"`
+	Won            int    `json:"won"`
+	Drawn          int    `json:"drawn"`
+	Lost           int    `json:"lost"`
+	GoalsFor       int    `json:"goals_for"`
+	GoalsAgainst   int    `json:"goals_against"`
+	GoalDifference int    `json:"goal_difference"`
+	Points         int    `json:"points"`
+}
+
+// DivisionStandings represents the standings table for a single division
+type DivisionStandings struct {
+	Division  string     `json:"division"`
+	Standings []Standing `json:"standings"`
+}
+
+// Season describes a league season referenced by Game.Season
+type Season struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Start string `json:"start"` // first day of the season, YYYY-MM-DD
+	End   string `json:"end"`   // last day of the season, YYYY-MM-DD
+}
+
+// GameFilter narrows a schedule by date range, upcoming games or venue
+type GameFilter struct {
+	From         string // inclusive start date, YYYY-MM-DD
+	To           string // inclusive end date, YYYY-MM-DD
+	UpcomingOnly bool
+	Venue        string // "home", "away" or empty for both
 }
 
 // LambdaGamesResponse represents the response from the games API
@@ -59,6 +98,32 @@ type LambdaGamesResponse struct {
 	Games []Game `json:"games"`
 }
 
+// SoccerGamesResponse is the JSON API response for a set of team codes
+type SoccerGamesResponse struct {
+	Teams []string `json:"teams"`
+	Count int      `json:"count"`
+	Games []Game   `json:"games"`
+}
+
+// SoccerTeamResponse is the JSON API response for a single team
+type SoccerTeamResponse struct {
+	Code  string `json:"code"`
+	Count int    `json:"count"`
+	Games []Game `json:"games"`
+}
+
+// APIError is the error body returned by the JSON API
+type APIError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIErrorResponse wraps an APIError in the JSON API error envelope
+type APIErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
 // Education represents an education entry
 type Education struct {
 	ID           int